@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mebaranov/aioncraft/database"
+	"github.com/mebaranov/aioncraft/input"
+	"github.com/mebaranov/aioncraft/scrapper"
+)
+
+// diffCommand compares two database files: aioncraft diff [-format md|json] [-o file] old.json new.json
+func diffCommand(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	format := fs.String("format", "md", "Report format: md or json")
+	out := fs.String("o", "", "Output file. Standard output is used if empty")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fmt.Fprintln(os.Stderr, "Usage: aioncraft diff [-format md|json] [-o file] <old database> <new database>")
+		return 2
+	}
+
+	dbs := make([]*database.Database, 2)
+	for i, path := range fs.Args() {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not read database (%v). Error: %v\n", path, err)
+			return 1
+		}
+		dbs[i], err = database.NewFromJson(data)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not unmarshal database (%v). Error: %v\n", path, err)
+			return 1
+		}
+	}
+
+	diff := database.Compare(dbs[0], dbs[1])
+	var report []byte
+	switch *format {
+	case "md", "markdown":
+		report = []byte(diff.Markdown(0))
+	case "json":
+		var err error
+		report, err = diff.JSON()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not marshal diff. Error: %v\n", err)
+			return 1
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown format: %v\n", *format)
+		return 2
+	}
+
+	if *out == "" {
+		os.Stdout.Write(report)
+		return 0
+	}
+	if err := ioutil.WriteFile(*out, report, 0666); err != nil {
+		fmt.Fprintf(os.Stderr, "Could not write report (%v). Error: %v\n", *out, err)
+		return 1
+	}
+
+	return 0
+}
+
+// ingestCommand parses dump files and prints what was parsed and skipped: aioncraft ingest [-json] [dump.json...]
+// Known dump files are checked if none are given.
+func ingestCommand(args []string) int {
+	fs := flag.NewFlagSet("ingest", flag.ContinueOnError)
+	asJson := fs.Bool("json", false, "Print reports as JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		dumps, err := findDumps(dataDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not find dumps. Error: %v\n", err)
+			return 1
+		}
+		if missing := missingCrafts(dumps); len(missing) > 0 {
+			fmt.Fprintf(os.Stderr, "No dumps for crafts: %v\n", missing)
+		}
+		for _, d := range dumps {
+			files = append(files, d.File)
+		}
+	}
+
+	s := scrapper.New()
+	total := &scrapper.IngestReport{}
+	reports := map[string]*scrapper.IngestReport{}
+	rv := 0
+	for _, path := range files {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not read dump (%v). Error: %v\n", path, err)
+			rv = 1
+			continue
+		}
+
+		report, err := s.Scrap(data, map[string]*database.Item{}, map[string]*database.Recipe{}, map[string]*database.Item{}, map[string]*database.Recipe{})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not scrap dump (%v). Error: %v\n", path, err)
+			rv = 1
+			continue
+		}
+		if len(report.Skipped) > 0 {
+			rv = 1
+		}
+		reports[path] = report
+		total.Add(report)
+	}
+
+	if *asJson {
+		data, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not marshal reports. Error: %v\n", err)
+			return 1
+		}
+		fmt.Println(string(data))
+		return rv
+	}
+
+	for _, path := range files {
+		if report, ok := reports[path]; ok {
+			fmt.Printf("== %v\n%v\n", path, report)
+		}
+	}
+	if len(reports) > 1 {
+		fmt.Printf("== Total\n%v", total)
+	}
+
+	return rv
+}
+
+// treeCommand writes craft trees of an item as graph files:
+// aioncraft tree [-race elyos] [-format dot|mermaid|png|all] [-o dir] <item name>
+func treeCommand(args []string) int {
+	fs := flag.NewFlagSet("tree", flag.ContinueOnError)
+	raceName := fs.String("race", "Elyos", "Race: Elyos or Asmodian")
+	format := fs.String("format", "all", "Graph format: dot, mermaid, png or all. All is dot and mermaid")
+	out := fs.String("o", ".", "Output directory")
+	dbFile := fs.String("db", dbPath, "Database file")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "Usage: aioncraft tree [-race elyos] [-format dot|mermaid|png|all] [-o dir] <item name>")
+		return 2
+	}
+
+	race, ok := database.ParseRace(*raceName)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown race: %v\n", *raceName)
+		return 2
+	}
+	formats := []string{*format}
+	if *format == "all" {
+		formats = []string{input.FormatDOT, input.FormatMermaid}
+	}
+
+	data, err := ioutil.ReadFile(*dbFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not read database (%v). Error: %v\n", *dbFile, err)
+		return 1
+	}
+	db, err := database.NewFromJson(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not unmarshal database (%v). Error: %v\n", *dbFile, err)
+		return 1
+	}
+
+	item := strings.Join(fs.Args(), " ")
+	trees := input.NewProcessor(db).Trees(race, item, nil, nil, database.DefaultLocale)
+	if len(trees) == 0 {
+		fmt.Fprintf(os.Stderr, "No recipes found for item: %v\n", item)
+		return 1
+	}
+
+	for _, f := range formats {
+		graph, err := input.RenderGraph(f, trees, database.DefaultLocale)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+
+		path := filepath.Join(*out, input.GraphFile(item, f))
+		if err := ioutil.WriteFile(path, []byte(graph), 0666); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not write graph (%v). Error: %v\n", path, err)
+			return 1
+		}
+		fmt.Println(path)
+	}
+
+	return 0
+}
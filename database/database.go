@@ -1,6 +1,10 @@
 package database
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
 
 type Race int
 
@@ -27,6 +31,7 @@ const (
 	Created = State(iota)
 	Scrapped
 	Named
+	Described
 )
 
 var Races = []Race{
@@ -41,12 +46,151 @@ var Crafts = []CraftType{
 	Tailor,
 	Alchemy,
 	Cooking,
+	Morph,
+}
+
+// RequiredCrafts are crafts every dump set is expected to have. No morph dumps are published yet, so morph recipes
+// are used when they are present, but their absence is not reported.
+var RequiredCrafts = []CraftType{
+	Handicraft,
+	Weapon,
+	Armor,
+	Tailor,
+	Alchemy,
+	Cooking,
+}
+
+var raceNames = map[Race]string{
+	Elyos:    "Elyos",
+	Asmodian: "Asmodian",
+}
+
+var craftNames = map[CraftType]string{
+	Handicraft: "Handicraft",
+	Weapon:     "Weaponsmith",
+	Armor:      "Armorsmith",
+	Tailor:     "Tailoring",
+	Alchemy:    "Alchemy",
+	Cooking:    "Cooking",
+	Morph:      "Morph",
+}
+
+// raceAliases are short forms of race names and their indexes shown in help.
+var raceAliases = map[string]Race{
+	"1":         Elyos,
+	"ely":       Elyos,
+	"e":         Elyos,
+	"light":     Elyos,
+	"2":         Asmodian,
+	"asmo":      Asmodian,
+	"asmodians": Asmodian,
+	"a":         Asmodian,
+	"dark":      Asmodian,
+}
+
+// craftAliases are other names crafts are known by, e.g. in codex dump file names.
+var craftAliases = map[string]CraftType{
+	"handiwork":      Handicraft,
+	"weapon":         Weapon,
+	"armor":          Armor,
+	"tailor":         Tailor,
+	"transformation": Morph,
+}
+
+// ParseCraft finds the craft by its name or alias, case insensitive.
+func ParseCraft(name string) (CraftType, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for ct, n := range craftNames {
+		if strings.ToLower(n) == name {
+			return ct, true
+		}
+	}
+	ct, ok := craftAliases[name]
+	return ct, ok
+}
+
+// ParseRace finds the race by its name or alias, case insensitive.
+func ParseRace(name string) (Race, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for r, n := range raceNames {
+		if strings.ToLower(n) == name {
+			return r, true
+		}
+	}
+	r, ok := raceAliases[name]
+	return r, ok
+}
+
+func (r Race) String() string {
+	if name, ok := raceNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("Race(%d)", int(r))
+}
+
+func (c CraftType) String() string {
+	if name, ok := craftNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("CraftType(%d)", int(c))
+}
+
+// MarshalJSON writes the race name, so reports read the same as their Markdown. Unknown races are written as
+// numbers. MarshalText is not implemented on purpose: races are map keys in saved files, which stay numeric.
+func (r Race) MarshalJSON() ([]byte, error) {
+	if name, ok := raceNames[r]; ok {
+		return json.Marshal(name)
+	}
+	return json.Marshal(int(r))
+}
+
+// UnmarshalJSON reads race names and the numbers saved by older versions.
+func (r *Race) UnmarshalJSON(data []byte) error {
+	var name string
+	if string(data) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(data, &name); err != nil {
+		return json.Unmarshal(data, (*int)(r))
+	}
+	race, ok := ParseRace(name)
+	if !ok {
+		return fmt.Errorf("unknown race %q", name)
+	}
+	*r = race
+	return nil
+}
+
+// MarshalJSON writes the craft name like Race.MarshalJSON does.
+func (c CraftType) MarshalJSON() ([]byte, error) {
+	if name, ok := craftNames[c]; ok {
+		return json.Marshal(name)
+	}
+	return json.Marshal(int(c))
+}
+
+// UnmarshalJSON reads craft names and the numbers saved by older versions.
+func (c *CraftType) UnmarshalJSON(data []byte) error {
+	var name string
+	if string(data) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(data, &name); err != nil {
+		return json.Unmarshal(data, (*int)(c))
+	}
+	ct, ok := ParseCraft(name)
+	if !ok {
+		return fmt.Errorf("unknown craft %q", name)
+	}
+	*c = ct
+	return nil
 }
 
 type Database struct {
 	Recipes    map[Race]map[CraftType]map[string]*Recipe
 	Items      map[Race]map[string]*Item
 	CurState   State
+	LastDiff   *Diff
 	SaveNeeded bool
 }
 
@@ -73,20 +217,89 @@ func NewFromJson(in []byte) (*Database, error) {
 	rv := &Database{}
 	err := json.Unmarshal(in, rv)
 	rv.SaveNeeded = false
+	if err == nil {
+		rv.ensure()
+	}
 
 	return rv, err
 }
 
+// Clone makes a deep copy of the database.
+func (d *Database) Clone() (*Database, error) {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromJson(data)
+}
+
+// ensure creates maps missing in databases saved by older versions.
+func (d *Database) ensure() {
+	if d.Items == nil {
+		d.Items = make(map[Race]map[string]*Item)
+	}
+	if d.Recipes == nil {
+		d.Recipes = make(map[Race]map[CraftType]map[string]*Recipe)
+	}
+
+	for _, r := range Races {
+		if d.Items[r] == nil {
+			d.Items[r] = make(map[string]*Item)
+		}
+		if d.Recipes[r] == nil {
+			d.Recipes[r] = make(map[CraftType]map[string]*Recipe)
+		}
+		for _, c := range Crafts {
+			if d.Recipes[r][c] == nil {
+				d.Recipes[r][c] = make(map[string]*Recipe)
+			}
+		}
+	}
+}
+
 func (d *Database) Save() ([]byte, error) {
 	d.SaveNeeded = false
 	return json.Marshal(d)
 }
 
+// RecipeMaps returns recipe maps of every race and craft.
+func (d *Database) RecipeMaps() []map[string]*Recipe {
+	rv := []map[string]*Recipe{}
+	for _, race := range Races {
+		for _, ct := range Crafts {
+			rv = append(rv, d.Recipes[race][ct])
+		}
+	}
+	return rv
+}
+
+// RecipeCount returns the amount of recipes of the craft which are not removed.
+func (d *Database) RecipeCount(race Race, ct CraftType) int {
+	rv := 0
+	for _, r := range d.Recipes[race][ct] {
+		if !r.Removed {
+			rv += 1
+		}
+	}
+	return rv
+}
+
+// EmptyCrafts lists required crafts having no recipes for the race.
+func (d *Database) EmptyCrafts(race Race) []CraftType {
+	rv := []CraftType{}
+	for _, ct := range RequiredCrafts {
+		if d.RecipeCount(race, ct) == 0 {
+			rv = append(rv, ct)
+		}
+	}
+	return rv
+}
+
 func (d *Database) RecipeByItem(race Race, ct CraftType, itemId string) *Recipe {
 	recipes := d.Recipes[race][ct]
 	var possibleRv *Recipe
 	for _, r := range recipes {
-		if r.ItemID == itemId {
+		if r.ItemID == itemId && !r.Removed {
 			if r.Count == 1 {
 				return r
 			}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseRace(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Race
+		ok   bool
+	}{
+		{"Elyos", Elyos, true},
+		{" asmodian ", Asmodian, true},
+		{"asmo", Asmodian, true},
+		{"light", Elyos, true},
+		{"2", Asmodian, true},
+		{"balaur", 0, false},
+	}
+
+	for _, tt := range tests {
+		if got, ok := ParseRace(tt.in); got != tt.want || ok != tt.ok {
+			t.Errorf("ParseRace(%q) = %v, %v, want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRaceJSON(t *testing.T) {
+	data, err := json.Marshal(&RecipeChange{Race: Asmodian, Craft: Weapon})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"race":"Asmodian","craft":"Weaponsmith"`) {
+		t.Errorf("Marshal() = %s, want race and craft names", data)
+	}
+
+	for _, in := range []string{`{"race":"Asmodian","craft":"Weaponsmith"}`, `{"race":1,"craft":1}`} {
+		ch := &RecipeChange{}
+		if err := json.Unmarshal([]byte(in), ch); err != nil || ch.Race != Asmodian || ch.Craft != Weapon {
+			t.Errorf("Unmarshal(%s) = %v %v, %v, want Asmodian Weaponsmith", in, ch.Race, ch.Craft, err)
+		}
+	}
+
+	saved, err := New().Save()
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if !strings.Contains(string(saved), `"Items":{"0":{},"1":{}}`) {
+		t.Errorf("Save() = %s, want numeric race keys", saved)
+	}
+}
@@ -0,0 +1,262 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+type ChangeKind string
+
+const (
+	RecipeAdded   = ChangeKind("added")
+	RecipeRemoved = ChangeKind("removed")
+	RecipeChanged = ChangeKind("changed")
+)
+
+type IngredientChange struct {
+	ItemID string `json:"itemId"`
+	Name   string `json:"name"`
+	Old    int    `json:"old"`
+	New    int    `json:"new"`
+}
+
+type RecipeChange struct {
+	Kind        ChangeKind          `json:"kind"`
+	Race        Race                `json:"race"`
+	Craft       CraftType           `json:"craft"`
+	ID          string              `json:"id"`
+	Name        string              `json:"name"`
+	OldLevel    int                 `json:"oldLevel"`
+	NewLevel    int                 `json:"newLevel"`
+	OldCount    int                 `json:"oldCount"`
+	NewCount    int                 `json:"newCount"`
+	OldItemID   string              `json:"oldItemId,omitempty"`
+	NewItemID   string              `json:"newItemId,omitempty"`
+	Ingredients []*IngredientChange `json:"ingredients,omitempty"`
+}
+
+// Diff lists recipe differences between two database snapshots.
+type Diff struct {
+	Created time.Time       `json:"created"`
+	Changes []*RecipeChange `json:"changes"`
+}
+
+// Compare finds recipes added, removed or changed in the new database comparing to the old one.
+// Recipes marked as removed are treated as missing.
+func Compare(old *Database, new *Database) *Diff {
+	rv := &Diff{Created: time.Now(), Changes: []*RecipeChange{}}
+
+	for _, race := range Races {
+		for _, ct := range allCrafts(old, new, race) {
+			oldRecs, newRecs := old.Recipes[race][ct], new.Recipes[race][ct]
+
+			for id, nr := range newRecs {
+				if nr.Removed {
+					continue
+				}
+				or, ok := oldRecs[id]
+				if !ok || or.Removed {
+					rv.Changes = append(rv.Changes, &RecipeChange{
+						Kind: RecipeAdded, Race: race, Craft: ct, ID: id, Name: nr.Name,
+						NewLevel: nr.Level, NewCount: nr.Count, NewItemID: nr.ItemID,
+					})
+					continue
+				}
+
+				if ch := compareRecipes(old, new, race, or, nr); ch != nil {
+					ch.Craft = ct
+					rv.Changes = append(rv.Changes, ch)
+				}
+			}
+
+			for id, or := range oldRecs {
+				if or.Removed {
+					continue
+				}
+				if nr, ok := newRecs[id]; !ok || nr.Removed {
+					rv.Changes = append(rv.Changes, &RecipeChange{
+						Kind: RecipeRemoved, Race: race, Craft: ct, ID: id, Name: or.Name,
+						OldLevel: or.Level, OldCount: or.Count, OldItemID: or.ItemID,
+					})
+				}
+			}
+		}
+	}
+
+	sort.Slice(rv.Changes, func(i, j int) bool {
+		a, b := rv.Changes[i], rv.Changes[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Race != b.Race {
+			return a.Race < b.Race
+		}
+		if a.Craft != b.Craft {
+			return a.Craft < b.Craft
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.ID < b.ID
+	})
+
+	return rv
+}
+
+func allCrafts(a *Database, b *Database, race Race) []CraftType {
+	set := map[CraftType]bool{}
+	for ct := range a.Recipes[race] {
+		set[ct] = true
+	}
+	for ct := range b.Recipes[race] {
+		set[ct] = true
+	}
+
+	rv := []CraftType{}
+	for ct := range set {
+		rv = append(rv, ct)
+	}
+	sort.Slice(rv, func(i, j int) bool { return rv[i] < rv[j] })
+
+	return rv
+}
+
+func compareRecipes(old *Database, new *Database, race Race, or *Recipe, nr *Recipe) *RecipeChange {
+	if or.Level == nr.Level && or.Count == nr.Count && or.ItemID == nr.ItemID && reflect.DeepEqual(or.Items, nr.Items) {
+		return nil
+	}
+
+	rv := &RecipeChange{
+		Kind: RecipeChanged, Race: race, ID: nr.ID, Name: nr.Name,
+		OldLevel: or.Level, NewLevel: nr.Level, OldCount: or.Count, NewCount: nr.Count,
+	}
+	if or.ItemID != nr.ItemID {
+		rv.OldItemID, rv.NewItemID = or.ItemID, nr.ItemID
+	}
+
+	ids := map[string]bool{}
+	for id := range or.Items {
+		ids[id] = true
+	}
+	for id := range nr.Items {
+		ids[id] = true
+	}
+	for id := range ids {
+		if or.Items[id] == nr.Items[id] {
+			continue
+		}
+		rv.Ingredients = append(rv.Ingredients, &IngredientChange{
+			ItemID: id,
+			Name:   itemName(race, id, new, old),
+			Old:    or.Items[id],
+			New:    nr.Items[id],
+		})
+	}
+	sort.Slice(rv.Ingredients, func(i, j int) bool {
+		return rv.Ingredients[i].Name < rv.Ingredients[j].Name
+	})
+
+	return rv
+}
+
+func itemName(race Race, id string, dbs ...*Database) string {
+	for _, db := range dbs {
+		if it, ok := db.Items[race][id]; ok && it.Name != "" {
+			return it.Name
+		}
+	}
+	return id
+}
+
+func (d *Diff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+func (d *Diff) Count(kind ChangeKind) int {
+	rv := 0
+	for _, ch := range d.Changes {
+		if ch.Kind == kind {
+			rv += 1
+		}
+	}
+	return rv
+}
+
+func (d *Diff) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// Markdown renders the diff as a report. Limit caps the amount of listed recipes per section, 0 lists all of them.
+func (d *Diff) Markdown(limit int) string {
+	rv := fmt.Sprintf("## Recipe changes (%v)\n", d.Created.Format("2006-01-02"))
+	if d.Empty() {
+		return rv + "\nNo changes.\n"
+	}
+
+	for _, sec := range []struct {
+		kind  ChangeKind
+		title string
+	}{
+		{RecipeAdded, "New recipes"},
+		{RecipeRemoved, "Removed recipes"},
+		{RecipeChanged, "Changed recipes"},
+	} {
+		count := d.Count(sec.kind)
+		if count == 0 {
+			continue
+		}
+
+		rv += fmt.Sprintf("\n**%v (%v)**\n", sec.title, count)
+		listed := 0
+		for _, ch := range d.Changes {
+			if ch.Kind != sec.kind {
+				continue
+			}
+			if limit > 0 && listed >= limit {
+				rv += fmt.Sprintf("- ...and %v more\n", count-listed)
+				break
+			}
+			rv += "- " + ch.markdown() + "\n"
+			listed += 1
+		}
+	}
+
+	return rv
+}
+
+func (ch *RecipeChange) markdown() string {
+	rv := fmt.Sprintf("%v %v: %v", ch.Race, ch.Craft, ch.Name)
+	switch ch.Kind {
+	case RecipeAdded:
+		return rv + fmt.Sprintf(" (level %v)", ch.NewLevel)
+	case RecipeRemoved:
+		return rv + fmt.Sprintf(" (level %v)", ch.OldLevel)
+	}
+
+	parts := []string{}
+	if ch.OldLevel != ch.NewLevel {
+		parts = append(parts, fmt.Sprintf("level %v → %v", ch.OldLevel, ch.NewLevel))
+	}
+	if ch.OldCount != ch.NewCount {
+		parts = append(parts, fmt.Sprintf("output x%v → x%v", ch.OldCount, ch.NewCount))
+	}
+	if ch.OldItemID != ch.NewItemID {
+		parts = append(parts, fmt.Sprintf("product %v → %v", ch.OldItemID, ch.NewItemID))
+	}
+	for _, ing := range ch.Ingredients {
+		switch {
+		case ing.Old == 0:
+			parts = append(parts, fmt.Sprintf("+ %v x%v", ing.Name, ing.New))
+		case ing.New == 0:
+			parts = append(parts, fmt.Sprintf("- %v x%v", ing.Name, ing.Old))
+		default:
+			parts = append(parts, fmt.Sprintf("%v x%v → x%v", ing.Name, ing.Old, ing.New))
+		}
+	}
+
+	return rv + ": " + strings.Join(parts, "; ")
+}
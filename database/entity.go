@@ -1,20 +1,76 @@
 package database
 
 import (
+	"time"
+
 	"github.com/mebaranov/aioncraft/utility"
 )
 
+// DefaultLocale is the locale of Item.Name.
+const DefaultLocale = "en"
+
 type Item struct {
 	Name  string
+	Names map[string]string
 	ID    string
 	Price *utility.TheInt
+	Info  *ItemInfo
+	// Updated is when the price was set. It is zero for prices set before it was tracked.
+	Updated time.Time
+}
+
+// ItemInfo is the item metadata from its codex page.
+type ItemInfo struct {
+	Grade       string
+	Category    string
+	VendorPrice int
+	Tradeable   bool
+	Stack       int
 }
 
 type Recipe struct {
-	Name   string
-	ID     string
-	ItemID string
-	Level  int
-	Count  int
-	Items  map[string]int
+	Name    string
+	ID      string
+	ItemID  string
+	Level   int
+	Count   int
+	Items   map[string]int
+	Removed bool
+	Info    *RecipeInfo
+}
+
+// RecipeInfo is the recipe metadata from its codex page.
+type RecipeInfo struct {
+	Source    string
+	Kinah     int
+	AP        int
+	Tier      string
+	WorkOrder bool
+}
+
+// LocalName returns the item name for the locale falling back to the default one.
+func (i *Item) LocalName(locale string) string {
+	if name, ok := i.Names[locale]; ok && name != "" {
+		return name
+	}
+	return i.Name
+}
+
+// VendorPrice returns the price NPC vendors sell the item for, 0 if they don't.
+func (i *Item) VendorPrice() int {
+	if i.Info == nil {
+		return 0
+	}
+	return i.Info.VendorPrice
+}
+
+// AllNames returns the item names in every known locale.
+func (i *Item) AllNames() []string {
+	rv := []string{i.Name}
+	for locale, name := range i.Names {
+		if locale != DefaultLocale && name != "" {
+			rv = append(rv, name)
+		}
+	}
+	return rv
 }
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"sort"
+)
+
+// RecipeRef points to a recipe of a craft.
+type RecipeRef struct {
+	Craft  CraftType
+	Recipe *Recipe
+}
+
+// Use is a recipe consuming an item. Depth 1 means the item is an ingredient of the recipe, depth 2 means it is an
+// ingredient of an ingredient and so on. Quantity is the amount of the item spent for a single craft of the recipe.
+type Use struct {
+	Race     Race
+	Craft    CraftType
+	Recipe   *Recipe
+	Quantity float64
+	Depth    int
+}
+
+// UsesIndex returns recipes consuming each item of the race, keyed by item ID. Removed recipes are skipped.
+func (d *Database) UsesIndex(race Race) map[string][]*RecipeRef {
+	rv := map[string][]*RecipeRef{}
+	for _, ct := range Crafts {
+		for _, rec := range d.Recipes[race][ct] {
+			if rec.Removed {
+				continue
+			}
+			for id := range rec.Items {
+				rv[id] = append(rv[id], &RecipeRef{Craft: ct, Recipe: rec})
+			}
+		}
+	}
+	return rv
+}
+
+// Uses finds recipes of every race which consume the item directly or through intermediate crafts.
+// Each recipe is listed once with the least depth. MaxDepth limits the search, 0 means no limit.
+func (d *Database) Uses(itemID string, maxDepth int) []*Use {
+	rv := []*Use{}
+
+	for _, race := range Races {
+		index := d.UsesIndex(race)
+		seen := map[string]bool{}
+
+		type step struct {
+			itemID   string
+			quantity float64
+		}
+		queue := []step{{itemID, 1}}
+		seenItems := map[string]bool{itemID: true}
+
+		for depth := 1; len(queue) > 0 && (maxDepth == 0 || depth <= maxDepth); depth++ {
+			next := []step{}
+			for _, st := range queue {
+				for _, ref := range index[st.itemID] {
+					if seen[ref.Recipe.ID] {
+						continue
+					}
+					seen[ref.Recipe.ID] = true
+
+					quantity := st.quantity * float64(ref.Recipe.Items[st.itemID])
+					rv = append(rv, &Use{Race: race, Craft: ref.Craft, Recipe: ref.Recipe, Quantity: quantity, Depth: depth})
+
+					// Products are followed only through recipes RecipeByItem picks for them, so the quantities
+					// match manuals and prices.
+					product := ref.Recipe.ItemID
+					if !seenItems[product] && ref.Recipe.Count > 0 && d.RecipeByItem(race, ref.Craft, product) == ref.Recipe {
+						seenItems[product] = true
+						next = append(next, step{product, quantity / float64(ref.Recipe.Count)})
+					}
+				}
+			}
+			queue = next
+		}
+	}
+
+	sort.SliceStable(rv, func(i, j int) bool {
+		a, b := rv[i], rv[j]
+		if a.Depth != b.Depth {
+			return a.Depth < b.Depth
+		}
+		if a.Race != b.Race {
+			return a.Race < b.Race
+		}
+		if a.Craft != b.Craft {
+			return a.Craft < b.Craft
+		}
+		if a.Recipe.Level != b.Recipe.Level {
+			return a.Recipe.Level < b.Recipe.Level
+		}
+		return a.Recipe.ID < b.Recipe.ID
+	})
+
+	return rv
+}
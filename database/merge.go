@@ -0,0 +1,114 @@
+package database
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/mebaranov/aioncraft/utility"
+)
+
+// MergeSummary describes what Merge changed.
+type MergeSummary struct {
+	Added      []string
+	Changed    []string
+	Removed    []string
+	Restored   []string
+	NewItems   int
+	SkippedFor []string
+}
+
+// Merge updates the database with recipes scrapped into fresh one. Prices and names of known items are kept, new
+// items get not available prices.
+// Recipes missing in fresh are marked as removed rather than deleted. Crafts without any recipes in fresh are
+// left intact, as it usually means the dump file is missing rather than the craft is gone.
+func (d *Database) Merge(fresh *Database) *MergeSummary {
+	d.ensure()
+	rv := &MergeSummary{}
+
+	for _, race := range Races {
+		for id, it := range fresh.Items[race] {
+			if _, ok := d.Items[race][id]; !ok {
+				if it.Price == nil {
+					name := it.Name
+					if name == "" {
+						name = id
+					}
+					it.Price = utility.NewInt(0, name)
+				}
+				d.Items[race][id] = it
+				rv.NewItems += 1
+			}
+		}
+
+		for _, ct := range Crafts {
+			newRecs := fresh.Recipes[race][ct]
+			if len(newRecs) == 0 {
+				if len(d.Recipes[race][ct]) > 0 {
+					rv.SkippedFor = append(rv.SkippedFor, fmt.Sprintf("%v %v", race, ct))
+				}
+				continue
+			}
+
+			oldRecs := d.Recipes[race][ct]
+			for id, rec := range newRecs {
+				old, ok := oldRecs[id]
+				if !ok {
+					oldRecs[id] = rec
+					rv.Added = append(rv.Added, rec.Name)
+					continue
+				}
+
+				if old.Removed {
+					old.Removed = false
+					rv.Restored = append(rv.Restored, rec.Name)
+				}
+				if !old.sameAs(rec) {
+					old.Name, old.ItemID, old.Level, old.Count, old.Items = rec.Name, rec.ItemID, rec.Level, rec.Count, rec.Items
+					rv.Changed = append(rv.Changed, rec.Name)
+				}
+			}
+
+			for id, rec := range oldRecs {
+				if _, ok := newRecs[id]; !ok && !rec.Removed {
+					rec.Removed = true
+					rv.Removed = append(rv.Removed, rec.Name)
+				}
+			}
+		}
+	}
+
+	for _, l := range [][]string{rv.Added, rv.Changed, rv.Removed, rv.Restored, rv.SkippedFor} {
+		sort.Strings(l)
+	}
+	d.SaveNeeded = true
+
+	return rv
+}
+
+func (r *Recipe) sameAs(o *Recipe) bool {
+	return r.Name == o.Name && r.ItemID == o.ItemID && r.Level == o.Level && r.Count == o.Count && reflect.DeepEqual(r.Items, o.Items)
+}
+
+func (m *MergeSummary) String() string {
+	rv := fmt.Sprintf("Recipes added: %v, changed: %v, removed: %v, restored: %v. New items: %v\n",
+		len(m.Added), len(m.Changed), len(m.Removed), len(m.Restored), m.NewItems)
+
+	for _, sec := range []struct {
+		title string
+		list  []string
+	}{
+		{"Added", m.Added},
+		{"Changed", m.Changed},
+		{"Removed", m.Removed},
+		{"Restored", m.Restored},
+		{"Not refreshed, as dumps have no recipes", m.SkippedFor},
+	} {
+		if len(sec.list) > 0 {
+			rv += fmt.Sprintf("%v:\n\t%v\n", sec.title, strings.Join(sec.list, "\n\t"))
+		}
+	}
+
+	return rv
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func loadFixture(t *testing.T, file string) *Database {
+	data, err := ioutil.ReadFile("testdata/" + file)
+	if err != nil {
+		t.Fatalf("Could not read %v: %v", file, err)
+	}
+	db, err := NewFromJson(data)
+	if err != nil {
+		t.Fatalf("Could not load %v: %v", file, err)
+	}
+	return db
+}
+
+func TestMerge(t *testing.T) {
+	db, fresh := loadFixture(t, "merge_old.json"), loadFixture(t, "merge_new.json")
+	got := db.Merge(fresh)
+
+	want := &MergeSummary{
+		Added:      []string{"Nail"},
+		Changed:    []string{"Plate"},
+		Removed:    []string{"Ring"},
+		Restored:   []string{"Chain"},
+		NewItems:   1,
+		SkippedFor: []string{"Asmodian Cooking"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge() = %+v, want %+v", got, want)
+	}
+
+	recs := db.Recipes[Elyos][Handicraft]
+	if !recs["r-ring"].Removed || recs["r-chain"].Removed {
+		t.Errorf("removed ring = %v, chain = %v, want true, false", recs["r-ring"].Removed, recs["r-chain"].Removed)
+	}
+	if items := recs["r-plate"].Items; !reflect.DeepEqual(items, map[string]int{"ingot": 3, "nail": 4}) {
+		t.Errorf("plate ingredients = %v, want the fresh ones", items)
+	}
+	if db.Recipes[Asmodian][Cooking]["r-bread"] == nil {
+		t.Errorf("bread recipe is dropped, want crafts missing in the dumps kept")
+	}
+	if price := db.Items[Elyos]["ingot"].Price; price.Value != 50 || len(price.NAReasons) != 0 {
+		t.Errorf("ingot price = %v, want the known one kept", price)
+	}
+	if price := db.Items[Elyos]["nail"].Price; price == nil || !reflect.DeepEqual(price.NAReasons, []string{"nail"}) {
+		t.Errorf("nail price = %v, want not available", price)
+	}
+	if !db.SaveNeeded {
+		t.Errorf("SaveNeeded = false, want true")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	old := loadFixture(t, "merge_old.json")
+	db, err := old.Clone()
+	if err != nil {
+		t.Fatalf("Clone() error = %v", err)
+	}
+	db.Merge(loadFixture(t, "merge_new.json"))
+	diff := Compare(old, db)
+
+	type change struct {
+		kind ChangeKind
+		id   string
+	}
+	got := []change{}
+	for _, ch := range diff.Changes {
+		got = append(got, change{ch.Kind, ch.ID})
+	}
+	want := []change{{RecipeAdded, "r-chain"}, {RecipeAdded, "r-nail"}, {RecipeChanged, "r-plate"}, {RecipeRemoved, "r-ring"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Compare() = %v, want %v", got, want)
+	}
+
+	plate := diff.Changes[2]
+	wantIngredients := []*IngredientChange{
+		{ItemID: "ingot", Name: "Ingot", Old: 2, New: 3},
+		{ItemID: "nail", Name: "nail", Old: 0, New: 4},
+	}
+	if plate.Craft != Handicraft || !reflect.DeepEqual(plate.Ingredients, wantIngredients) {
+		t.Errorf("plate change = %+v, want ingredients %+v", plate, wantIngredients)
+	}
+	if !Compare(db, db).Empty() {
+		t.Errorf("Compare() of the same database is not empty")
+	}
+}
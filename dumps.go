@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/martian/v3/log"
+	"github.com/mebaranov/aioncraft/database"
+)
+
+var dataDir = "data"
+
+// manifestName is the file in dataDir describing dump files. Without it dumps are discovered by their names.
+const manifestName = "dumps.json"
+
+// Dump is a codex recipes dump file of a single craft.
+type Dump struct {
+	File   string
+	Craft  database.CraftType
+	Races  []database.Race
+	Source string
+}
+
+type manifestEntry struct {
+	File   string   `json:"file"`
+	Craft  string   `json:"craft"`
+	Races  []string `json:"races,omitempty"`
+	Source string   `json:"source,omitempty"`
+}
+
+// findDumps reads the manifest from dir or, if there is none, discovers dumps named after crafts, e.g. alchemy.json
+// or handiwork.json.
+func findDumps(dir string) ([]*Dump, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, manifestName))
+	if err == nil {
+		return parseManifest(dir, data)
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("Could not read manifest. Error: %v", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	rv := []*Dump{}
+	for _, f := range files {
+		name := strings.TrimSuffix(filepath.Base(f), filepath.Ext(f))
+		ct, ok := database.ParseCraft(name)
+		if !ok {
+			continue
+		}
+		rv = append(rv, &Dump{File: f, Craft: ct, Races: database.Races})
+	}
+
+	return rv, nil
+}
+
+func parseManifest(dir string, data []byte) ([]*Dump, error) {
+	entries := []*manifestEntry{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("Could not parse manifest. Error: %v", err)
+	}
+
+	rv := []*Dump{}
+	for i, e := range entries {
+		if e.File == "" {
+			return nil, fmt.Errorf("Manifest entry %v has no file", i)
+		}
+		ct, ok := database.ParseCraft(e.Craft)
+		if !ok {
+			return nil, fmt.Errorf("Unknown craft in manifest entry %v: %q", i, e.Craft)
+		}
+
+		d := &Dump{File: e.File, Craft: ct, Races: database.Races, Source: e.Source}
+		if !filepath.IsAbs(d.File) {
+			d.File = filepath.Join(dir, d.File)
+		}
+		if len(e.Races) > 0 {
+			d.Races = []database.Race{}
+			for _, name := range e.Races {
+				r, ok := database.ParseRace(name)
+				if !ok {
+					return nil, fmt.Errorf("Unknown race in manifest entry %v: %q", i, name)
+				}
+				d.Races = append(d.Races, r)
+			}
+		}
+		rv = append(rv, d)
+	}
+
+	return rv, nil
+}
+
+// hasRace reports if recipes of the race are taken from the dump.
+func (d *Dump) hasRace(race database.Race) bool {
+	for _, r := range d.Races {
+		if r == race {
+			return true
+		}
+	}
+	return false
+}
+
+// missingCrafts lists required crafts which have no dump.
+func missingCrafts(dumps []*Dump) []database.CraftType {
+	rv := []database.CraftType{}
+	for _, ct := range database.RequiredCrafts {
+		found := false
+		for _, d := range dumps {
+			found = found || d.Craft == ct
+		}
+		if !found {
+			rv = append(rv, ct)
+		}
+	}
+	return rv
+}
+
+// checkCrafts logs crafts having no recipes, as prices and manuals for them are silently missing otherwise.
+func checkCrafts(db *database.Database) {
+	for _, race := range database.Races {
+		if empty := db.EmptyCrafts(race); len(empty) > 0 {
+			log.Errorf("No %v recipes for crafts: %v. Add their dumps to %v", race, empty, dataDir)
+		}
+	}
+}
@@ -6,4 +6,5 @@ require (
 	cloud.google.com/go/storage v1.16.0
 	github.com/bwmarrin/discordgo v0.23.2
 	github.com/google/martian/v3 v3.2.1
+	golang.org/x/image v0.0.0-20190802002840-cff245a6509b
 )
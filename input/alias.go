@@ -0,0 +1,267 @@
+package input
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/mebaranov/aioncraft/database"
+	"github.com/mebaranov/aioncraft/utility"
+)
+
+const defaultPrefix = "/c"
+
+var commands = []string{"help", "race", "set", "price", "how", "import", "export", "perm", "readonly", "inv", "prefix", "alias", "lang", "patch", "announce", "uses", "level", "craftable", "profit", "tree", "watch", "digest", "stale"}
+
+// aliasPresets are command translations for community servers. They can be loaded with '/c alias lang <language>'.
+var aliasPresets = map[string]map[string]string{
+	"de": {
+		"hilfe":       "help",
+		"rasse":       "race",
+		"setzen":      "set",
+		"preis":       "price",
+		"wie":         "how",
+		"importieren": "import",
+		"exportieren": "export",
+		"inventar":    "inv",
+		"sprache":     "lang",
+	},
+	"fr": {
+		"aide":       "help",
+		"definir":    "set",
+		"prix":       "price",
+		"comment":    "how",
+		"importer":   "import",
+		"exporter":   "export",
+		"inventaire": "inv",
+		"langue":     "lang",
+	},
+	"ru": {
+		"помощь":     "help",
+		"раса":       "race",
+		"установить": "set",
+		"цена":       "price",
+		"как":        "how",
+		"импорт":     "import",
+		"экспорт":    "export",
+		"инвентарь":  "inv",
+		"префикс":    "prefix",
+		"псевдоним":  "alias",
+		"разрешения": "perm",
+		"язык":       "lang",
+	},
+	"ko": {
+		"도움말":  "help",
+		"종족":   "race",
+		"설정":   "set",
+		"가격":   "price",
+		"제작법":  "how",
+		"가져오기": "import",
+		"내보내기": "export",
+		"인벤토리": "inv",
+		"언어":   "lang",
+	},
+}
+
+func (g *Guild) prefix() string {
+	if g.Prefix == "" {
+		return defaultPrefix
+	}
+	return g.Prefix
+}
+
+// command cuts the prefix out of the message. Prefix ending with a letter or a digit has to be followed by a space.
+func (g *Guild) command(content string) (string, bool) {
+	prefix := g.prefix()
+	if !strings.HasPrefix(content, prefix) {
+		return "", false
+	}
+
+	rest := content[len(prefix):]
+	last := []rune(prefix)[len([]rune(prefix))-1]
+	if (unicode.IsLetter(last) || unicode.IsDigit(last)) && !strings.HasPrefix(rest, " ") {
+		return "", false
+	}
+
+	return strings.TrimSpace(rest), true
+}
+
+// resolve translates an alias to the command it stands for.
+func (g *Guild) resolve(cmd string) string {
+	if c, ok := g.Aliases[cmd]; ok {
+		return c
+	}
+	return cmd
+}
+
+func isCommand(cmd string) bool {
+	for _, c := range commands {
+		if c == cmd {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Discord) setPrefix(s *discordgo.Session, m *discordgo.MessageCreate, g *Guild, args string) {
+	prefix := strings.TrimSpace(args)
+	if prefix == "" {
+		msg := tr(g.Language, "prefix.current", g.prefix())
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+		return
+	}
+	if m.GuildID != "" && !d.isAdmin(s, m) {
+		msg := tr(g.Language, "prefix.admin")
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+		return
+	}
+	if strings.ContainsAny(prefix, " \t\n") || len([]rune(prefix)) > 10 {
+		msg := tr(g.Language, "prefix.invalid")
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+		return
+	}
+
+	d.update(m, func(g *Guild) {
+		g.Prefix = prefix
+		if prefix == defaultPrefix {
+			g.Prefix = ""
+		}
+		d.SaveNeeded = true
+	})
+
+	msg := tr(g.Language, "prefix.set", prefix)
+	utility.SendMonitored(s, &m.ChannelID, &msg)
+}
+
+func (d *Discord) alias(s *discordgo.Session, m *discordgo.MessageCreate, g *Guild, args string) {
+	params := strings.Fields(strings.ToLower(args))
+	if len(params) == 0 || params[0] == "list" {
+		msg := tr(g.Language, "alias.none")
+		if len(g.Aliases) > 0 {
+			msg = tr(g.Language, "alias.list", g.aliasList())
+		}
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+		return
+	}
+
+	if m.GuildID != "" && !d.isAdmin(s, m) {
+		msg := tr(g.Language, "alias.admin")
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+		return
+	}
+
+	var msg string
+	switch {
+	case params[0] == "add" && len(params) == 3:
+		alias, cmd := params[1], g.resolve(params[2])
+		if !isCommand(cmd) {
+			msg = tr(g.Language, "cmd.unknown", params[2])
+			break
+		}
+		if isCommand(alias) {
+			msg = tr(g.Language, "alias.command", alias)
+			break
+		}
+		d.update(m, func(g *Guild) {
+			g.addAlias(alias, cmd)
+			d.SaveNeeded = true
+		})
+		msg = tr(g.Language, "alias.added", g.prefix(), alias, cmd)
+	case params[0] == "remove" && len(params) == 2:
+		if _, ok := g.Aliases[params[1]]; !ok {
+			msg = tr(g.Language, "alias.unknown", params[1])
+			break
+		}
+		d.update(m, func(g *Guild) {
+			delete(g.Aliases, params[1])
+			d.SaveNeeded = true
+		})
+		msg = tr(g.Language, "alias.removed", params[1])
+	case params[0] == "lang" && len(params) == 2:
+		preset, ok := aliasPresets[params[1]]
+		if !ok {
+			msg = tr(g.Language, "alias.nopreset", params[1], presetLanguages())
+			break
+		}
+		d.update(m, func(g *Guild) {
+			for alias, cmd := range preset {
+				g.addAlias(alias, cmd)
+			}
+			d.SaveNeeded = true
+			msg = tr(g.Language, "alias.preset", g.aliasList())
+		})
+	case params[0] == "clear" && len(params) == 1:
+		d.update(m, func(g *Guild) {
+			g.Aliases = nil
+			d.SaveNeeded = true
+		})
+		msg = tr(g.Language, "alias.cleared")
+	default:
+		msg = tr(g.Language, "alias.usage", g.prefix(), presetLanguages())
+	}
+
+	utility.SendMonitored(s, &m.ChannelID, &msg)
+}
+
+func (g *Guild) addAlias(alias string, cmd string) {
+	if g.Aliases == nil {
+		g.Aliases = map[string]string{}
+	}
+	g.Aliases[alias] = cmd
+}
+
+func (g *Guild) aliasList() string {
+	list := []string{}
+	for alias, cmd := range g.Aliases {
+		list = append(list, fmt.Sprintf("%v -> %v", alias, cmd))
+	}
+	sort.Strings(list)
+
+	return strings.Join(list, ", ")
+}
+
+func presetLanguages() string {
+	langs := []string{}
+	for l := range aliasPresets {
+		langs = append(langs, l)
+	}
+	sort.Strings(langs)
+
+	return strings.Join(langs, "|")
+}
+
+func (d *Discord) language(s *discordgo.Session, m *discordgo.MessageCreate, g *Guild, args string) {
+	lang := strings.ToLower(strings.TrimSpace(args))
+	if lang == "" {
+		msg := tr(g.Language, "lang.set", g.language())
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+		return
+	}
+	if m.GuildID != "" && !d.isAdmin(s, m) {
+		msg := tr(g.Language, "lang.admin")
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+		return
+	}
+	if !isLanguage(lang) {
+		msg := tr(g.Language, "lang.unknown", lang, languages())
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+		return
+	}
+
+	d.update(m, func(g *Guild) {
+		g.Language = lang
+		d.SaveNeeded = true
+	})
+
+	msg := tr(lang, "lang.set", lang)
+	utility.SendMonitored(s, &m.ChannelID, &msg)
+}
+
+func (g *Guild) language() string {
+	if g.Language == "" {
+		return database.DefaultLocale
+	}
+	return g.Language
+}
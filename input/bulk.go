@@ -0,0 +1,244 @@
+package input
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mebaranov/aioncraft/database"
+)
+
+// csvColumns are names of columns in headers of CSV imports. Export writes the first, second and last one.
+var csvColumns = map[string]bool{"id": true, "item": true, "name": true, "price": true}
+
+type priceRow struct {
+	line  int
+	item  string
+	price string
+}
+
+type jsonPriceRow struct {
+	Item  string      `json:"item"`
+	ID    string      `json:"id"`
+	Name  string      `json:"name"`
+	Price json.Number `json:"price"`
+}
+
+func (p *Processor) Import(cmd Command) string {
+	rows, err := parsePriceRows(cmd.Data)
+	if err != nil {
+		return tr(cmd.Lang, "import.parse", err)
+	}
+	if len(rows) == 0 {
+		return tr(cmd.Lang, "import.empty")
+	}
+
+	set, errs, ids := 0, []string{}, []string{}
+	for _, row := range rows {
+		if row.item == "" {
+			errs = append(errs, tr(cmd.Lang, "import.emptyname", row.line))
+			continue
+		}
+
+		price, ok := parsePrice(row.price)
+		if !ok {
+			errs = append(errs, tr(cmd.Lang, "import.price", row.line, row.item, row.price))
+			continue
+		}
+
+		it := p.findItem(cmd.Race, row.item)
+		if it == nil {
+			errs = append(errs, tr(cmd.Lang, "import.notfound", row.line, row.item))
+			continue
+		}
+
+		set += 1
+		ids = append(ids, it.ID)
+		if cmd.Book != nil {
+			cmd.Book[it.ID] = price
+			continue
+		}
+		it.Price.NAReasons = []string{}
+		it.Price.Value = price
+		it.Updated = time.Now()
+	}
+
+	if set > 0 && cmd.Book == nil {
+		p.db.SaveNeeded = true
+	}
+	if set > 0 {
+		p.notify(cmd.Race, ids...)
+	}
+
+	rv := tr(cmd.Lang, "import.done", set, len(rows))
+	if len(errs) > 0 {
+		rv += "\n" + tr(cmd.Lang, "import.skipped") + "\n" + strings.Join(errs, "\n")
+	}
+	return rv
+}
+
+func (p *Processor) Export(cmd Command) string {
+	items := []*database.Item{}
+	for _, it := range p.db.Items[cmd.Race] {
+		if _, ok := cmd.Book[it.ID]; ok || (it.Price != nil && len(it.Price.NAReasons) == 0) {
+			items = append(items, it)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Name == items[j].Name {
+			return items[i].ID < items[j].ID
+		}
+		return items[i].Name < items[j].Name
+	})
+
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	w.Write([]string{"id", "item", "price"})
+	for _, it := range items {
+		w.Write([]string{it.ID, it.Name, strconv.Itoa(p.itemPrice(cmd.Race, it.ID, newPricing(cmd)).Value)})
+	}
+	w.Flush()
+
+	return buf.String()
+}
+
+func (p *Processor) findItem(race database.Race, name string) *database.Item {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if it, ok := p.db.Items[race][name]; ok {
+		return it
+	}
+
+	for _, it := range p.db.Items[race] {
+		if matchAny(it, func(n string) bool { return strings.ToLower(n) == name }) {
+			return it
+		}
+	}
+
+	return nil
+}
+
+func parsePriceRows(data []byte) ([]*priceRow, error) {
+	data = bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	switch data[0] {
+	case '[', '{':
+		return parseJsonRows(data)
+	default:
+		return parseCsvRows(data)
+	}
+}
+
+func parseJsonRows(data []byte) ([]*priceRow, error) {
+	if data[0] == '{' {
+		m := map[string]json.Number{}
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, err
+		}
+
+		names := make([]string, 0, len(m))
+		for name := range m {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		rv := make([]*priceRow, 0, len(m))
+		for i, name := range names {
+			rv = append(rv, &priceRow{i + 1, name, m[name].String()})
+		}
+		return rv, nil
+	}
+
+	arr := []*jsonPriceRow{}
+	if err := json.Unmarshal(data, &arr); err != nil {
+		return nil, err
+	}
+
+	rv := make([]*priceRow, 0, len(arr))
+	for i, r := range arr {
+		item := r.Item
+		if item == "" {
+			item = r.Name
+		}
+		if item == "" {
+			item = r.ID
+		}
+		rv = append(rv, &priceRow{i + 1, item, r.Price.String()})
+	}
+	return rv, nil
+}
+
+func parseCsvRows(data []byte) ([]*priceRow, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	if bytes.Count(data, []byte(";")) > bytes.Count(data, []byte(",")) {
+		r.Comma = ';'
+	}
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	rv := []*priceRow{}
+	for i, rec := range records {
+		if len(rec) == 0 || (len(rec) == 1 && strings.TrimSpace(rec[0]) == "") {
+			continue
+		}
+		// Header is optional. The first line is one if all its columns are known names, otherwise its errors
+		// are reported like of any other line.
+		if i == 0 && isCsvHeader(rec) {
+			continue
+		}
+
+		row := &priceRow{line: i + 1}
+		switch len(rec) {
+		case 1:
+			row.price = rec[0]
+		case 2:
+			row.item, row.price = strings.TrimSpace(rec[0]), rec[1]
+		default:
+			// id,item,price as produced by export. ID is preferred as it is unambiguous.
+			row.item, row.price = strings.TrimSpace(rec[0]), rec[len(rec)-1]
+			if row.item == "" {
+				row.item = strings.TrimSpace(rec[1])
+			}
+		}
+		rv = append(rv, row)
+	}
+
+	return rv, nil
+}
+
+func isCsvHeader(rec []string) bool {
+	for _, col := range rec {
+		if !csvColumns[strings.ToLower(strings.TrimSpace(col))] {
+			return false
+		}
+	}
+	return true
+}
+
+// parsePrice reads imported prices: plain numbers, numbers with thousands separated by commas or spaces, and
+// amounts like 1.5k or 2kk. The comma of amounts with a suffix is a decimal one, like in 1,5k.
+func parsePrice(s string) (int, bool) {
+	s = strings.NewReplacer(" ", "", "\u00a0", "").Replace(strings.ToLower(strings.TrimSpace(s)))
+	if strings.TrimRight(s, "km") != s {
+		s = strings.Replace(s, ",", ".", 1)
+	} else {
+		s = strings.Replace(s, ",", "", -1)
+	}
+
+	if price, err := strconv.Atoi(s); err == nil && price >= 0 {
+		return price, true
+	}
+	return parseKinah(s)
+}
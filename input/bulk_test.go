@@ -0,0 +1,128 @@
+package input
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mebaranov/aioncraft/database"
+	"github.com/mebaranov/aioncraft/utility"
+)
+
+func TestParsePriceRows(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []priceRow
+	}{
+		{"comma", "Ore,10\nIngot,20", []priceRow{{1, "Ore", "10"}, {2, "Ingot", "20"}}},
+		{"semicolon", "Ore;1,5k\nIngot;20", []priceRow{{1, "Ore", "1,5k"}, {2, "Ingot", "20"}}},
+		{"header", "item,price\nOre,10", []priceRow{{2, "Ore", "10"}}},
+		{"export", "id,item,price\nore,Ore,10\n,Ingot,20", []priceRow{{2, "ore", "10"}, {3, "Ingot", "20"}}},
+		{"suffix in first row", "Ore,1.5k\nIngot,2kk", []priceRow{{1, "Ore", "1.5k"}, {2, "Ingot", "2kk"}}},
+		{"quoted separators in first row", "Ore,\"12,500\"", []priceRow{{1, "Ore", "12,500"}}},
+		{"unknown header", "thing,cost\nOre,10", []priceRow{{1, "thing", "cost"}, {2, "Ore", "10"}}},
+		{"bom and blank lines", "\xef\xbb\xbfOre,10\n\nIngot,20\n", []priceRow{{1, "Ore", "10"}, {2, "Ingot", "20"}}},
+		{"json object", `{"Ingot": 20, "Ore": 10}`, []priceRow{{1, "Ingot", "20"}, {2, "Ore", "10"}}},
+		{"json array", `[{"id": "ore", "price": 10}, {"name": "Ingot", "price": 20}]`, []priceRow{{1, "ore", "10"}, {2, "Ingot", "20"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := parsePriceRows([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("parsePriceRows() error = %v", err)
+			}
+			got := []priceRow{}
+			for _, row := range rows {
+				got = append(got, *row)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePriceRows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePrice(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+		ok   bool
+	}{
+		{"10", 10, true},
+		{" 0 ", 0, true},
+		{"12,500", 12500, true},
+		{"12 500", 12500, true},
+		{"1.5k", 1500, true},
+		{"1,5k", 1500, true},
+		{"2kk", 2000000, true},
+		{"-5", 0, false},
+		{"cheap", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		if got, ok := parsePrice(tt.in); got != tt.want || ok != tt.ok {
+			t.Errorf("parsePrice(%q) = %v, %v, want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func bulkDatabase() *database.Database {
+	db := database.New()
+	db.Items[database.Elyos]["ore"] = &database.Item{ID: "ore", Name: "Ore", Price: &utility.TheInt{Value: 10}}
+	db.Items[database.Elyos]["ingot"] = &database.Item{ID: "ingot", Name: "Ingot", Price: utility.NewInt(0, "Ingot")}
+	return db
+}
+
+func TestImport(t *testing.T) {
+	db := bulkDatabase()
+	p := NewProcessor(db)
+	got := p.Import(Command{Race: database.Elyos, Data: []byte("Ore,1.5k\nGold,10\nIngot,cheap\n,5\nIngot,\"2,000\""), Lang: database.DefaultLocale})
+
+	for _, want := range []string{"Imported 2 of 5 prices.", "Row 2: item (Gold) was not found", "Row 3: wrong price for Ingot: \"cheap\"", "Row 4: item name is empty"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Import() = %q, want %q in it", got, want)
+		}
+	}
+	if price := db.Items[database.Elyos]["ore"].Price; price.Value != 1500 {
+		t.Errorf("ore price = %v, want 1500", price)
+	}
+	if price := db.Items[database.Elyos]["ingot"].Price; price.Value != 2000 || len(price.NAReasons) != 0 {
+		t.Errorf("ingot price = %v, want 2000", price)
+	}
+	if !db.SaveNeeded {
+		t.Errorf("SaveNeeded = false, want true")
+	}
+}
+
+func TestImportBook(t *testing.T) {
+	db := bulkDatabase()
+	book := map[string]int{}
+	NewProcessor(db).Import(Command{Race: database.Elyos, Data: []byte("item,price\nOre,20"), Book: book, Lang: database.DefaultLocale})
+
+	if book["ore"] != 20 || db.Items[database.Elyos]["ore"].Price.Value != 10 {
+		t.Errorf("book = %v, shared price = %v, want the personal price only", book, db.Items[database.Elyos]["ore"].Price)
+	}
+}
+
+func TestExport(t *testing.T) {
+	p := NewProcessor(bulkDatabase())
+	tests := []struct {
+		name string
+		book map[string]int
+		want string
+	}{
+		{"shared", nil, "id,item,price\nore,Ore,10\n"},
+		{"book", map[string]int{"ingot": 30, "ore": 5}, "id,item,price\ningot,Ingot,30\nore,Ore,5\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Export(Command{Race: database.Elyos, Book: tt.book}); got != tt.want {
+				t.Errorf("Export() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package input
 import (
 	"bufio"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"strconv"
 	"strings"
@@ -45,18 +46,14 @@ func (c *CLI) Start(cmdc chan Command, outc chan string) {
 				continue
 			}
 
-			switch strings.ToLower(cmdArr[1]) {
-			case "1", "elyos":
-				c.race = database.Elyos
-				c.isRaceSelected = true
-				fmt.Println("Race is set to Elyos")
-			case "2", "asmodian":
-				c.race = database.Asmodian
-				c.isRaceSelected = true
-				fmt.Println("Race is set to Asmodian")
-			default:
+			r, ok := database.ParseRace(cmdArr[1])
+			if !ok {
 				fmt.Println("Wrong race")
+				continue
 			}
+			c.race = r
+			c.isRaceSelected = true
+			fmt.Printf("Race is set to %v\n", r.String())
 		case "set":
 			if !c.isRaceSelected {
 				fmt.Println("Select the race first")
@@ -115,6 +112,135 @@ func (c *CLI) Start(cmdc chan Command, outc chan string) {
 				Out:    outc,
 			}
 			fmt.Println(<-outc)
+		case "uses":
+			if !c.isRaceSelected {
+				fmt.Println("Select the race first")
+				continue
+			}
+			if len(cmdArr) != 2 {
+				fmt.Println("Wrong command format")
+				continue
+			}
+
+			cmdc <- Command{
+				Action: Uses,
+				Race:   c.race,
+				Item:   cmdArr[1],
+				Out:    outc,
+			}
+			fmt.Println(<-outc)
+		case "level":
+			if !c.isRaceSelected {
+				fmt.Println("Select the race first")
+				continue
+			}
+			if len(cmdArr) != 2 {
+				fmt.Println("Wrong command format")
+				continue
+			}
+			ct, from, to, ok := parseLevelArgs(database.DefaultLocale, cmdArr[1])
+			if !ok {
+				fmt.Println(tr(database.DefaultLocale, "level.usage"))
+				continue
+			}
+
+			cmdc <- Command{
+				Action: Level,
+				Race:   c.race,
+				Craft:  ct,
+				From:   from,
+				To:     to,
+				Out:    outc,
+			}
+			fmt.Println(<-outc)
+		case "craftable":
+			if !c.isRaceSelected {
+				fmt.Println("Select the race first")
+				continue
+			}
+			if len(cmdArr) != 2 {
+				fmt.Println("Wrong command format")
+				continue
+			}
+
+			cmdc <- Command{
+				Action: Craftable,
+				Race:   c.race,
+				Data:   []byte(cmdArr[1]),
+				Out:    outc,
+			}
+			fmt.Println(<-outc)
+		case "profit":
+			if !c.isRaceSelected {
+				fmt.Println("Select the race first")
+				continue
+			}
+			if len(cmdArr) != 2 {
+				fmt.Println("Wrong command format")
+				continue
+			}
+			budget, ct, maxLevel, ok := parseProfitArgs(database.DefaultLocale, cmdArr[1])
+			if !ok {
+				fmt.Println(tr(database.DefaultLocale, "profit.usage"))
+				continue
+			}
+
+			cmdc <- Command{
+				Action: Profit,
+				Race:   c.race,
+				Craft:  ct,
+				Price:  budget,
+				To:     maxLevel,
+				Out:    outc,
+			}
+			fmt.Println(<-outc)
+		case "import":
+			if !c.isRaceSelected {
+				fmt.Println("Select the race first")
+				continue
+			}
+			if len(cmdArr) < 2 {
+				fmt.Println("Wrong command format")
+				continue
+			}
+
+			path := strings.Join(cmdArr[1:], ":")
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				fmt.Printf("Could not read file (%v): %v\n", path, err)
+				continue
+			}
+
+			cmdc <- Command{
+				Action: Import,
+				Race:   c.race,
+				Data:   data,
+				Out:    outc,
+			}
+			fmt.Println(<-outc)
+		case "export":
+			if !c.isRaceSelected {
+				fmt.Println("Select the race first")
+				continue
+			}
+
+			cmdc <- Command{
+				Action: Export,
+				Race:   c.race,
+				Out:    outc,
+			}
+			out := <-outc
+			if len(cmdArr) < 2 {
+				fmt.Println(out)
+				continue
+			}
+
+			path := strings.Join(cmdArr[1:], ":")
+			if err := ioutil.WriteFile(path, []byte(out), 0666); err != nil {
+				fmt.Printf("Could not write file (%v): %v\n", path, err)
+				continue
+			}
+			fmt.Printf("Prices are exported to %v\n", path)
 		default:
 			fmt.Printf("Command \"%v\" is not known\n", cmd)
 		}
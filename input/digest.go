@@ -0,0 +1,352 @@
+package input
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/mebaranov/aioncraft/database"
+	"github.com/mebaranov/aioncraft/utility"
+)
+
+// Kinds of digests.
+const (
+	DigestProfit  = "profit"
+	DigestStale   = "stale"
+	DigestMissing = "missing"
+)
+
+var digestKinds = []string{DigestProfit, DigestStale, DigestMissing}
+
+const (
+	digestLimit = 15
+	// staleDays is the default age of prices which need updating.
+	staleDays     = 30
+	digestHour    = 9
+	digestTick    = 10 * time.Minute
+	digestDateFmt = "2006-01-02"
+)
+
+// Digest is a report posted to the channel every day after Hour UTC. Days is the age of stale prices.
+type Digest struct {
+	Kind    string
+	Channel string
+	Hour    int
+	Days    int
+	Last    time.Time
+}
+
+// due reports if the digest was not posted since the last time its hour passed.
+func (dg *Digest) due(now time.Time) bool {
+	now = now.UTC()
+	slot := time.Date(now.Year(), now.Month(), now.Day(), dg.Hour, 0, 0, 0, time.UTC)
+	if now.Before(slot) {
+		slot = slot.AddDate(0, 0, -1)
+	}
+	return dg.Last.Before(slot)
+}
+
+// Digest renders the cmd.Format report with shared prices. Cmd.Count is the age of stale prices in days.
+// Nothing is returned if there is nothing to report.
+func (p *Processor) Digest(cmd Command) string {
+	switch cmd.Format {
+	case DigestProfit:
+		return p.profitDigest(cmd)
+	case DigestStale:
+		return p.staleDigest(cmd)
+	case DigestMissing:
+		return p.missingDigest(cmd)
+	}
+	return ""
+}
+
+// recipesByItem returns recipes RecipeByItem picks for their products, so bulk variants are not listed twice.
+func (p *Processor) recipesByItem(race database.Race, ct database.CraftType) []*database.Recipe {
+	rv := []*database.Recipe{}
+	for _, rec := range p.db.Recipes[race][ct] {
+		if !rec.Removed && p.db.RecipeByItem(race, ct, rec.ItemID) == rec {
+			rv = append(rv, rec)
+		}
+	}
+	sort.Slice(rv, func(i, j int) bool {
+		return rv[i].ID < rv[j].ID
+	})
+	return rv
+}
+
+func (p *Processor) profitDigest(cmd Command) string {
+	type line struct {
+		ct     database.CraftType
+		rec    *database.Recipe
+		cost   int
+		income int
+	}
+
+	pr := newPricing(cmd)
+	lines := []*line{}
+	for _, ct := range database.Crafts {
+		for _, rec := range p.recipesByItem(cmd.Race, ct) {
+			sell := p.itemPrice(cmd.Race, rec.ItemID, pr)
+			if len(sell.NAReasons) != 0 || sell.Value <= 0 {
+				continue
+			}
+			cost := p.priceByRecipe(cmd.Race, ct, rec.ID, true, pr)
+			if len(cost.NAReasons) != 0 || sell.Value*rec.Count <= cost.Value {
+				continue
+			}
+			lines = append(lines, &line{ct, rec, cost.Value, sell.Value * rec.Count})
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].income-lines[i].cost > lines[j].income-lines[j].cost
+	})
+
+	rv := tr(cmd.Lang, "digest.profit")
+	for i, l := range lines {
+		if i >= digestLimit {
+			break
+		}
+		rv += "\n\t" + tr(cmd.Lang, "digest.profitline", p.productName(cmd.Race, l.rec, cmd.Lang), tr(cmd.Lang, "craft."+l.ct.String()), l.rec.Level, l.income-l.cost, l.cost, l.income)
+	}
+	return rv
+}
+
+// staleDigest lists prices older than cmd.Count days. Thresholds of categories from cmd.Stale are kept.
+func (p *Processor) staleDigest(cmd Command) string {
+	days := cmd.Count
+	if days <= 0 {
+		days = staleDays
+	}
+	st := &Staleness{Days: map[string]int{}}
+	if cmd.Stale != nil {
+		for category, d := range cmd.Stale.Days {
+			st.Days[category] = d
+		}
+	}
+	st.Days[""] = days
+
+	now := time.Now()
+	items := []*database.Item{}
+	for _, it := range p.db.Items[cmd.Race] {
+		if st.isStale(it, now) && !p.vendorPriced(cmd.Race, it.ID, nil) {
+			items = append(items, it)
+		}
+	}
+	if len(items) == 0 {
+		return ""
+	}
+	sortByUpdated(items)
+
+	rv := tr(cmd.Lang, "digest.stale", days)
+	for i, it := range items {
+		if i >= digestLimit {
+			rv += "\n\t" + tr(cmd.Lang, "stale.more", len(items)-i)
+			break
+		}
+		rv += "\n\t" + tr(cmd.Lang, "digest.staleline", it.LocalName(cmd.Lang), it.Price.Value, updatedDate(it, cmd.Lang))
+	}
+	return rv
+}
+
+func updatedDate(it *database.Item, lang string) string {
+	if it.Updated.IsZero() {
+		return tr(lang, "digest.nodate")
+	}
+	return it.Updated.UTC().Format(digestDateFmt)
+}
+
+// missingDigest counts recipe estimates each missing price makes unknown.
+func (p *Processor) missingDigest(cmd Command) string {
+	pr := newPricing(cmd)
+	counts := map[string]int{}
+	for _, ct := range database.Crafts {
+		for _, rec := range p.recipesByItem(cmd.Race, ct) {
+			for _, reason := range unique(p.priceByRecipe(cmd.Race, ct, rec.ID, true, pr).NAReasons) {
+				counts[reason] += 1
+			}
+		}
+	}
+	if len(counts) == 0 {
+		return ""
+	}
+
+	reasons := make([]string, 0, len(counts))
+	for reason := range counts {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if counts[reasons[i]] != counts[reasons[j]] {
+			return counts[reasons[i]] > counts[reasons[j]]
+		}
+		return reasons[i] < reasons[j]
+	})
+
+	rv := tr(cmd.Lang, "digest.missing")
+	for i, reason := range reasons {
+		if i >= digestLimit {
+			break
+		}
+		rv += "\n\t" + tr(cmd.Lang, "digest.missingline", reason, counts[reason])
+	}
+	return rv
+}
+
+func (d *Discord) digestLoop() {
+	for now := range time.Tick(digestTick) {
+		d.postDigests(now)
+	}
+}
+
+// postDigests posts digests which are due. They are marked posted and their commands are made under the lock,
+// reports are rendered and sent without it.
+func (d *Discord) postDigests(now time.Time) {
+	cmds, channels := []Command{}, []string{}
+	d.mu.Lock()
+	for _, g := range d.Guilds {
+		for _, dg := range g.Digests {
+			if !dg.due(now) {
+				continue
+			}
+			dg.Last = now
+			d.SaveNeeded = true
+			if cmd, ok := digestCommand(g, dg); ok {
+				cmds, channels = append(cmds, cmd), append(channels, dg.Channel)
+			}
+		}
+	}
+	d.mu.Unlock()
+
+	for i, cmd := range cmds {
+		d.postDigest(cmd, channels[i])
+	}
+}
+
+// digestCommand makes the report command of the digest. There is none if the race of its channel is not selected.
+func digestCommand(g *Guild, dg *Digest) (Command, bool) {
+	race, ok := g.race(dg.Channel, "")
+	if !ok {
+		return Command{}, false
+	}
+
+	return Command{
+		Action: DigestReport,
+		Race:   race,
+		Format: dg.Kind,
+		Count:  dg.Days,
+		Stale:  g.staleness(),
+		Lang:   g.Language,
+	}, true
+}
+
+func (d *Discord) postDigest(cmd Command, channel string) {
+	out := make(chan string, 1)
+	cmd.Out = out
+	d.cmdc <- cmd
+	if msg := <-out; msg != "" {
+		utility.SendMonitored(d.s, &channel, &msg)
+	}
+}
+
+// digest manages digests of the current channel: digest list|add <kind> [hour] [days]|remove <kind>|now <kind>.
+func (d *Discord) digest(s *discordgo.Session, m *discordgo.MessageCreate, g *Guild, args string) {
+	if m.GuildID == "" {
+		msg := tr(g.Language, "cmd.guildonly")
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+		return
+	}
+
+	params := strings.Fields(strings.ToLower(args))
+	op := ""
+	if len(params) > 0 {
+		op = params[0]
+	}
+	if op == "" || op == "list" {
+		msg := tr(g.Language, "digest.none")
+		if len(g.Digests) > 0 {
+			msg = tr(g.Language, "digest.title")
+			for _, dg := range g.Digests {
+				msg += "\n\t" + tr(g.Language, "digest.line", dg.Kind, dg.Channel, dg.Hour)
+				if dg.Kind == DigestStale {
+					msg += " " + tr(g.Language, "digest.days", dg.Days)
+				}
+			}
+		}
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+		return
+	}
+
+	kind := ""
+	if len(params) > 1 {
+		for _, k := range digestKinds {
+			if params[1] == k {
+				kind = k
+			}
+		}
+	}
+	if kind == "" || (op != "add" && op != "remove" && op != "now") {
+		msg := tr(g.Language, "digest.usage", g.prefix(), strings.Join(digestKinds, "|"))
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+		return
+	}
+
+	if !d.isAdmin(s, m) {
+		msg := tr(g.Language, "digest.admin")
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+		return
+	}
+	if op == "now" {
+		if cmd, ok := digestCommand(g, &Digest{Kind: kind, Channel: m.ChannelID, Days: staleDays}); ok {
+			d.postDigest(cmd, m.ChannelID)
+		}
+		return
+	}
+
+	dg := &Digest{Kind: kind, Channel: m.ChannelID, Hour: digestHour, Days: staleDays, Last: time.Now()}
+	if len(params) > 2 {
+		if h, err := strconv.Atoi(params[2]); err == nil && h >= 0 && h < 24 {
+			dg.Hour = h
+		}
+	}
+	if len(params) > 3 {
+		if days, err := strconv.Atoi(params[3]); err == nil && days > 0 {
+			dg.Days = days
+		}
+	}
+
+	removed := false
+	d.update(m, func(g *Guild) {
+		// There is a single digest of each kind per channel
+		for i, dg := range g.Digests {
+			if dg.Kind == kind && dg.Channel == m.ChannelID {
+				g.Digests = append(g.Digests[:i], g.Digests[i+1:]...)
+				removed = true
+				break
+			}
+		}
+		if op == "add" {
+			g.Digests = append(g.Digests, dg)
+		}
+		if removed || op == "add" {
+			d.SaveNeeded = true
+		}
+	})
+
+	if op == "remove" {
+		key := "digest.removed"
+		if !removed {
+			key = "digest.notfound"
+		}
+		msg := tr(g.Language, key, kind)
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+		return
+	}
+
+	msg := tr(g.Language, "digest.added", kind, dg.Hour)
+	utility.SendMonitored(s, &m.ChannelID, &msg)
+}
@@ -3,9 +3,14 @@ package input
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/google/martian/v3/log"
@@ -21,24 +26,45 @@ var intents = [...]discordgo.Intent{
 type Guild struct {
 	Race           database.Race
 	IsRaceSelected bool
+	ChannelRaces   map[string]database.Race
+	UserRaces      map[string]database.Race
+	Roles          map[string][]string
+	ReadOnly       bool
+	Book           map[database.Race]map[string]int
+	Inventory      map[database.Race]map[string]int
+	Prefix         string
+	Aliases        map[string]string
+	Language       string
+	Announce       string
+	Announced      time.Time
+	Watches        []*Watch
+	Digests        []*Digest
+	Stale          *Staleness
 	cmdc           chan Command
 	outc           chan string
 }
 
+// Discord keeps settings of guilds and users. Mu guards them, as handlers and background loops run concurrently.
 type Discord struct {
 	Token      string
 	Guilds     map[string]*Guild
+	Users      map[string]*Guild
 	SaveNeeded bool
+	mu         sync.Mutex
 	s          *discordgo.Session
 	readyChan  chan bool
 	cmdc       chan Command
 	outc       chan string
+	changes    chan bool
+	pending    map[database.Race]map[string]bool
+	pendingMu  sync.Mutex
 }
 
 func NewDiscord(token string) *Discord {
 	return &Discord{
 		Token:      token,
 		Guilds:     map[string]*Guild{},
+		Users:      map[string]*Guild{},
 		SaveNeeded: true,
 	}
 }
@@ -47,11 +73,22 @@ func NewDiscordFromJson(data []byte) (*Discord, error) {
 	rv := &Discord{}
 	err := json.Unmarshal(data, rv)
 	rv.SaveNeeded = false
+	if rv.Users == nil {
+		rv.Users = map[string]*Guild{}
+	}
 
 	return rv, err
 }
 
 const timeout = time.Second * 10
+const downloadTimeout = time.Second * 30
+const attachmentLimit = 1 << 20
+
+const inviteLink = "https://discord.com/oauth2/authorize?client_id=862485931013177354&scope=bot+messages.read"
+const sourceLink = "https://github.com/MeBaranov/aioncraft"
+
+// helpTopics lists commands in the order help describes them. Each one has a "help.<command>" message.
+var helpTopics = []string{"help", "race", "set", "price", "how", "uses", "level", "profit", "tree", "import", "export", "perm", "readonly", "prefix", "patch", "announce", "digest", "lang", "alias", "inv", "stale", "watch", "craftable"}
 
 func (d *Discord) Start(cmdc chan Command, outc chan string) {
 	var err error
@@ -62,6 +99,7 @@ func (d *Discord) Start(cmdc chan Command, outc chan string) {
 	d.readyChan = make(chan bool)
 	d.cmdc = cmdc
 	d.outc = outc
+	d.changes = make(chan bool, 1)
 
 	d.s.AddHandler(d.ready)
 	d.s.AddHandler(d.guildCreate)
@@ -74,25 +112,42 @@ func (d *Discord) Start(cmdc chan Command, outc chan string) {
 	select {
 	case <-d.readyChan:
 		log.Infof("Bot connected sucessfully")
+		go d.watchLoop()
+		go d.digestLoop()
 	case <-time.After(timeout):
 		panic("Bot could not connect in time with token: " + d.Token)
 	}
 }
 
 func (d *Discord) Save() ([]byte, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
 	d.SaveNeeded = false
 	return json.Marshal(d)
 }
 
+// NeedsSave reports if settings changed since the last save.
+func (d *Discord) NeedsSave() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.SaveNeeded
+}
+
 func (d *Discord) ready(s *discordgo.Session, r *discordgo.Ready) {
 	d.readyChan <- true
 }
 
 func (d *Discord) guildCreate(s *discordgo.Session, r *discordgo.GuildCreate) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
 	gid := r.Guild.ID
 	if g, ok := d.Guilds[gid]; ok {
 		g.cmdc = d.cmdc
 		g.outc = d.outc
+		go d.announcePatch(s, g)
 		return
 	}
 	d.Guilds[gid] = &Guild{
@@ -104,118 +159,566 @@ func (d *Discord) guildCreate(s *discordgo.Session, r *discordgo.GuildCreate) {
 	log.Infof("Added guild with ID: %v, Name: %v\n", r.Guild.ID, r.Guild.Name)
 }
 
+// user returns settings for direct messages with the user, creating them on the first use.
+func (d *Discord) user(id string) *Guild {
+	u, ok := d.Users[id]
+	if !ok {
+		u = &Guild{}
+		d.Users[id] = u
+		d.SaveNeeded = true
+		log.Infof("Added user with ID: %v\n", id)
+	}
+	u.cmdc = d.cmdc
+	u.outc = d.outc
+
+	return u
+}
+
+// storedInventory returns a copy of the inventory the user keeps for the race, nil if there is none. Unlike user,
+// it does not create settings for the user, so only changing them does.
+func (d *Discord) storedInventory(id string, race database.Race) map[string]int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if u, ok := d.Users[id]; ok && u.Inventory[race] != nil {
+		return copyCounts(u.Inventory[race])
+	}
+	return nil
+}
+
+// settings finds the settings of the message and cuts the command out of it. A copy of the settings is returned, so
+// handlers read it without holding the lock and change the kept settings with update.
+func (d *Discord) settings(m *discordgo.MessageCreate) (*Guild, string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	isDM := m.GuildID == ""
+	var g *Guild
+	if isDM {
+		g = d.Users[m.Author.ID]
+		if g == nil {
+			g = &Guild{}
+		}
+	} else {
+		g = d.Guilds[m.GuildID]
+	}
+	if g == nil {
+		return nil, "", false
+	}
+
+	msg, ok := g.command(m.Content)
+	if !ok {
+		return nil, "", false
+	}
+	if isDM {
+		g = d.user(m.Author.ID)
+	}
+
+	view, err := g.clone()
+	if err != nil {
+		log.Errorf("Could not copy settings. Error: %v", err)
+		return nil, "", false
+	}
+	return view, msg, true
+}
+
+// update changes the kept settings of the message under the lock. Change gets them rather than the copy handlers read.
+func (d *Discord) update(m *discordgo.MessageCreate, change func(g *Guild)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	g := d.Guilds[m.GuildID]
+	if m.GuildID == "" {
+		g = d.user(m.Author.ID)
+	}
+	if g != nil {
+		change(g)
+	}
+}
+
+// clone makes a deep copy of the settings.
+func (g *Guild) clone() (*Guild, error) {
+	data, err := json.Marshal(g)
+	if err != nil {
+		return nil, err
+	}
+	rv := &Guild{cmdc: g.cmdc, outc: g.outc}
+	return rv, json.Unmarshal(data, rv)
+}
+
+// mergeCounts applies changes made to after, a copy of live taken as before, to live.
+func mergeCounts(live map[string]int, before map[string]int, after map[string]int) {
+	for id := range before {
+		if _, ok := after[id]; !ok {
+			delete(live, id)
+		}
+	}
+	for id, count := range after {
+		if old, ok := before[id]; !ok || old != count {
+			live[id] = count
+		}
+	}
+}
+
+func (g *Guild) book(race database.Race) map[string]int {
+	if g.Book == nil {
+		g.Book = map[database.Race]map[string]int{}
+	}
+	if g.Book[race] == nil {
+		g.Book[race] = map[string]int{}
+	}
+
+	return g.Book[race]
+}
+
+func (g *Guild) inventory(race database.Race) map[string]int {
+	if g.Inventory == nil {
+		g.Inventory = map[database.Race]map[string]int{}
+	}
+	if g.Inventory[race] == nil {
+		g.Inventory[race] = map[string]int{}
+	}
+
+	return g.Inventory[race]
+}
+
 func (d *Discord) messageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
-	if m.Author.ID == s.State.User.ID || m.Author.Bot || !strings.HasPrefix(m.Content, "/c ") {
+	if m.Author.ID == s.State.User.ID || m.Author.Bot {
 		return
 	}
-	g := d.Guilds[m.GuildID]
-	if g == nil {
+	g, msg, ok := d.settings(m)
+	if !ok {
+		return
+	}
+
+	isDM := m.GuildID == ""
+	// Arguments may start on the next line, e.g. pasted lists
+	name, args := msg, ""
+	if idx := strings.IndexFunc(msg, unicode.IsSpace); idx >= 0 {
+		name, args = msg[:idx], strings.TrimSpace(msg[idx:])
+	}
+
+	cmd := g.resolve(strings.ToLower(name))
+	if perm, ok := commandPerms[cmd]; ok && cmd != "race" && !d.checkPermission(s, m, g, perm) {
 		return
 	}
 
-	msg := strings.TrimSpace(m.Content[3:])
-	cmds := strings.SplitN(msg, " ", 2)
+	race, isRaceSelected := g.race(m.ChannelID, m.Author.ID)
+	if cmd != "race" {
+		var raceArg string
+		args, raceArg = extractRace(args)
+		if raceArg != "" {
+			r, ok := database.ParseRace(raceArg)
+			if !ok {
+				msg := tr(g.Language, "race.wrong", raceArg)
+				utility.SendMonitored(s, &m.ChannelID, &msg)
+				return
+			}
+			race, isRaceSelected = r, true
+		}
+	}
+
+	// Direct messages use personal prices, guilds share the database ones
+	var book map[string]int
+	if isDM && isRaceSelected {
+		book = g.book(race)
+	}
 
-	cmd := strings.ToLower(cmds[0])
 	switch cmd {
 	case "race":
-		r := strings.ToLower(cmds[1])
-		switch r {
-		case "1", "elyos":
-			g.Race = database.Elyos
-			g.IsRaceSelected = true
-			d.SaveNeeded = true
-			msg := "Race is set to Elyos"
-			utility.SendMonitored(s, &m.ChannelID, &msg)
-		case "2", "asmodian":
-			g.Race = database.Asmodian
-			g.IsRaceSelected = true
-			d.SaveNeeded = true
-			msg := "Race is set to Asmodian"
-			utility.SendMonitored(s, &m.ChannelID, &msg)
-		default:
-			msg := "Wrong race selected"
-			utility.SendMonitored(s, &m.ChannelID, &msg)
-		}
+		d.race(s, m, g, args)
 		return
 	case "set":
-		if !g.IsRaceSelected {
-			msg := "Select the race first (see /c help)"
+		if !isRaceSelected {
+			msg := tr(g.Language, "race.first", g.prefix())
 			utility.SendMonitored(s, &m.ChannelID, &msg)
 			return
 		}
-		idx := strings.LastIndex(cmds[1], " ")
-		priceStr := strings.TrimSpace(cmds[1][idx+1:])
-		item := strings.TrimSpace(cmds[1][:idx])
+		idx := strings.LastIndex(args, " ")
+		if idx < 0 {
+			msg := tr(g.Language, "set.format")
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+		priceStr := strings.TrimSpace(args[idx+1:])
+		item := strings.TrimSpace(args[:idx])
 		if priceStr == "" || item == "" {
-			msg := "Wrong command format: Could not find item or price section"
+			msg := tr(g.Language, "set.format")
 			utility.SendMonitored(s, &m.ChannelID, &msg)
 			return
 		}
 
 		price, err := strconv.Atoi(priceStr)
 		if err != nil {
-			msg := "Wrong command format: Could not parse price: " + priceStr
+			msg := tr(g.Language, "set.parse", priceStr)
 			utility.SendMonitored(s, &m.ChannelID, &msg)
 			return
 		}
 
+		before := copyCounts(book)
 		g.cmdc <- Command{
 			Action: Set,
-			Race:   g.Race,
+			Race:   race,
 			Item:   item,
 			Price:  price,
+			Book:   book,
+			Stale:  g.staleness(),
+			Lang:   g.Language,
 			Out:    g.outc,
 		}
 		msg := <-g.outc
+		if isDM {
+			d.saveBook(m, race, before, book)
+		}
 		utility.SendMonitored(s, &m.ChannelID, &msg)
 	case "price":
-		if !g.IsRaceSelected {
-			msg := "Select the race first (see /c help)"
+		if !isRaceSelected {
+			msg := tr(g.Language, "race.first", g.prefix())
 			utility.SendMonitored(s, &m.ChannelID, &msg)
 			return
 		}
 
 		g.cmdc <- Command{
 			Action: Price,
-			Race:   g.Race,
-			Item:   cmds[1],
+			Race:   race,
+			Item:   args,
+			Book:   book,
+			Stale:  g.staleness(),
+			Lang:   g.Language,
+			Out:    g.outc,
+		}
+		msg := <-g.outc
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+	case "uses":
+		if !isRaceSelected {
+			msg := tr(g.Language, "race.first", g.prefix())
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+
+		g.cmdc <- Command{
+			Action: Uses,
+			Race:   race,
+			Item:   args,
+			Lang:   g.Language,
 			Out:    g.outc,
 		}
 		msg := <-g.outc
 		utility.SendMonitored(s, &m.ChannelID, &msg)
+	case "level":
+		if !isRaceSelected {
+			msg := tr(g.Language, "race.first", g.prefix())
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+		ct, from, to, ok := parseLevelArgs(g.Language, args)
+		if !ok {
+			msg := tr(g.Language, "level.usage")
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+
+		g.cmdc <- Command{
+			Action: Level,
+			Race:   race,
+			Craft:  ct,
+			From:   from,
+			To:     to,
+			Book:   book,
+			Stale:  g.staleness(),
+			Lang:   g.Language,
+			Out:    g.outc,
+		}
+		msg := <-g.outc
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+	case "craftable":
+		if !isRaceSelected {
+			msg := tr(g.Language, "race.first", g.prefix())
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+
+		// A pasted list or an attached file is used instead of the stored inventory
+		var data []byte
+		if !strings.EqualFold(args, "list") {
+			data = []byte(args)
+		}
+		if args == "" && len(m.Attachments) > 0 {
+			var err error
+			data, err = download(m.Attachments[0])
+			if err != nil {
+				msg := tr(g.Language, "attach.load", err)
+				utility.SendMonitored(s, &m.ChannelID, &msg)
+				return
+			}
+		}
+
+		g.cmdc <- Command{
+			Action:    Craftable,
+			Race:      race,
+			Data:      data,
+			Inventory: d.storedInventory(m.Author.ID, race),
+			Book:      book,
+			Stale:     g.staleness(),
+			Lang:      g.Language,
+			Out:       g.outc,
+		}
+		msg := <-g.outc
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+	case "profit":
+		if !isRaceSelected {
+			msg := tr(g.Language, "race.first", g.prefix())
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+		budget, ct, maxLevel, ok := parseProfitArgs(g.Language, args)
+		if !ok {
+			msg := tr(g.Language, "profit.usage")
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+
+		// The reply comes from another goroutine, so it goes over a private channel not to mix with other replies.
+		out := make(chan string, 1)
+		g.cmdc <- Command{
+			Action:    Profit,
+			Race:      race,
+			Craft:     ct,
+			Price:     budget,
+			To:        maxLevel,
+			Inventory: d.storedInventory(m.Author.ID, race),
+			Book:      book,
+			Stale:     g.staleness(),
+			Lang:      g.Language,
+			Out:       out,
+		}
+		msg := <-out
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+	case "tree":
+		if !isRaceSelected {
+			msg := tr(g.Language, "race.first", g.prefix())
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+
+		format, item := FormatMermaid, args
+		if fields := strings.Fields(args); len(fields) > 1 {
+			if _, ok := GraphExt[strings.ToLower(fields[0])]; ok {
+				format, item = strings.ToLower(fields[0]), strings.TrimSpace(strings.TrimPrefix(args, fields[0]))
+			}
+		}
+		if item == "" {
+			msg := tr(g.Language, "tree.usage")
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+
+		g.cmdc <- Command{
+			Action: Graph,
+			Race:   race,
+			Item:   item,
+			Format: format,
+			Book:   book,
+			Stale:  g.staleness(),
+			Lang:   g.Language,
+			Out:    g.outc,
+		}
+		data := <-g.outc
+		if data == "" {
+			msg := tr(g.Language, "how.notfound", item)
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+		utility.SendFile(s, &m.ChannelID, GraphFile(item, format), []byte(data))
 	case "how":
-		if !g.IsRaceSelected {
-			msg := "Select the race first (see /c help)"
+		if !isRaceSelected {
+			msg := tr(g.Language, "race.first", g.prefix())
 			utility.SendMonitored(s, &m.ChannelID, &msg)
 			return
 		}
 
+		// Both replies come over a private channel, so another message of the guild can't take them in between.
+		out := make(chan string, 1)
 		g.cmdc <- Command{
 			Action: Help,
-			Race:   g.Race,
-			Item:   cmds[1],
+			Race:   race,
+			Item:   args,
+			Book:   book,
+			Stale:  g.staleness(),
+			Lang:   g.Language,
+			Out:    out,
+		}
+		msg := <-out
+
+		// The tree image is attached unless it is too large, then the text manual is enough.
+		g.cmdc <- Command{
+			Action: Graph,
+			Race:   race,
+			Item:   args,
+			Format: FormatPNG,
+			Book:   book,
+			Stale:  g.staleness(),
+			Lang:   g.Language,
+			Out:    out,
+		}
+		if data := <-out; data != "" {
+			utility.SendFileWithMessage(s, &m.ChannelID, &msg, GraphFile(args, FormatPNG), []byte(data))
+			return
+		}
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+	case "import":
+		if !isRaceSelected {
+			msg := tr(g.Language, "race.first", g.prefix())
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+		if len(m.Attachments) == 0 {
+			msg := tr(g.Language, "import.attach")
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+
+		data, err := download(m.Attachments[0])
+		if err != nil {
+			msg := tr(g.Language, "attach.load", err)
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+
+		before := copyCounts(book)
+		g.cmdc <- Command{
+			Action: Import,
+			Race:   race,
+			Data:   data,
+			Book:   book,
+			Stale:  g.staleness(),
+			Lang:   g.Language,
 			Out:    g.outc,
 		}
 		msg := <-g.outc
+		if isDM {
+			d.saveBook(m, race, before, book)
+		}
 		utility.SendMonitored(s, &m.ChannelID, &msg)
+	case "export":
+		if !isRaceSelected {
+			msg := tr(g.Language, "race.first", g.prefix())
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+
+		g.cmdc <- Command{
+			Action: Export,
+			Race:   race,
+			Book:   book,
+			Stale:  g.staleness(),
+			Lang:   g.Language,
+			Out:    g.outc,
+		}
+		data := <-g.outc
+		utility.SendFile(s, &m.ChannelID, fmt.Sprintf("prices_%v.csv", strings.ToLower(race.String())), []byte(data))
+	case "perm", "readonly":
+		if isDM {
+			msg := tr(g.Language, "cmd.guildonly")
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+		if cmd == "perm" {
+			d.perm(s, m, g, args)
+		} else {
+			d.readOnly(s, m, g, args)
+		}
+	case "prefix":
+		d.setPrefix(s, m, g, args)
+	case "lang":
+		d.language(s, m, g, args)
+	case "announce":
+		d.announce(s, m, g, args)
+	case "patch":
+		d.patch(s, m, g, args)
+	case "alias":
+		d.alias(s, m, g, args)
+	case "digest":
+		d.digest(s, m, g, args)
+	case "stale":
+		d.staleCommand(s, m, g, race, isRaceSelected, args)
+	case "watch":
+		d.watch(s, m, g, race, isRaceSelected, book, args)
+	case "inv":
+		if !isRaceSelected {
+			msg := tr(g.Language, "race.first", g.prefix())
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+		d.inventory(s, m, g, race, args)
 	case "help":
-		msg := "" +
-			"Following commands are supported: \n" +
-			"\t'/c help - show this help\n'" +
-			"\t'/c set <item name> <price>' - set a price for an item. Exact name is required.\n" +
-			"\t'/c price <item name>' - shows a craft price estimate. You can use regular expressions for the name.\n" +
-			"\t'/c how <item name>' - shows how to craft an item. Exact name is required."
-		if !g.IsRaceSelected {
-			msg = "You should select a race using one of the following commands:\n\t'/c race Elyos' - for Elyos\n\t'/c race Asmodian' - for Asmodian.\n\n You can change the race in the future."
-		}
-
-		msg += "\n\nTo add me to your server use this link: https://discord.com/oauth2/authorize?client_id=862485931013177354&scope=bot+messages.read\n"
-		msg += "My source code is there: https://github.com/MeBaranov/aioncraft"
+		msg := tr(g.Language, "help.title")
+		for _, c := range helpTopics {
+			if c == "lang" {
+				msg += "\n\t" + tr(g.Language, "help.lang", languages())
+				continue
+			}
+			msg += "\n\t" + tr(g.Language, "help."+c)
+		}
+		msg += "\n" + tr(g.Language, "help.dm") + "\n" + tr(g.Language, "help.raceopt")
+		if !isRaceSelected {
+			msg = tr(g.Language, "help.norace")
+		}
+
+		if isRaceSelected {
+			g.cmdc <- Command{
+				Action: MissingCrafts,
+				Race:   race,
+				Lang:   g.Language,
+				Out:    g.outc,
+			}
+			if warn := <-g.outc; warn != "" {
+				msg += "\n\n" + warn
+			}
+		}
+
+		if g.prefix() != defaultPrefix {
+			msg = strings.Replace(msg, "'"+defaultPrefix+" ", "'"+g.prefix()+" ", -1)
+			msg += "\n\n" + tr(g.Language, "help.prefixnote", g.prefix())
+		}
+		if len(g.Aliases) > 0 {
+			msg += "\n\n" + tr(g.Language, "alias.list", g.aliasList())
+		}
+
+		msg += "\n\n" + tr(g.Language, "help.invite", inviteLink) + "\n"
+		msg += tr(g.Language, "help.source", sourceLink)
 		utility.SendMonitored(s, &m.ChannelID, &msg)
 	default:
-		msg := fmt.Sprintf("Command \"%v\" is not known", cmd)
+		msg := tr(g.Language, "cmd.unknown", cmd)
 		utility.SendMonitored(s, &m.ChannelID, &msg)
 		return
 	}
 }
+
+// saveBook keeps personal prices set by a command. The command changed book, a copy of the kept one taken as before.
+func (d *Discord) saveBook(m *discordgo.MessageCreate, race database.Race, before map[string]int, book map[string]int) {
+	d.update(m, func(g *Guild) {
+		mergeCounts(g.book(race), before, book)
+		d.SaveNeeded = true
+	})
+}
+
+var downloadClient = &http.Client{Timeout: downloadTimeout}
+
+func download(a *discordgo.MessageAttachment) ([]byte, error) {
+	if a.Size > attachmentLimit {
+		return nil, fmt.Errorf("file is too big (%v bytes, %v allowed)", a.Size, attachmentLimit)
+	}
+
+	resp, err := downloadClient.Get(a.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %v", resp.Status)
+	}
+
+	return ioutil.ReadAll(io.LimitReader(resp.Body, attachmentLimit))
+}
@@ -0,0 +1,132 @@
+package input
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Graph formats of craft trees.
+const (
+	FormatDOT     = "dot"
+	FormatMermaid = "mermaid"
+	FormatPNG     = "png"
+)
+
+// GraphExt maps graph formats to file extensions.
+var GraphExt = map[string]string{
+	FormatDOT:     "dot",
+	FormatMermaid: "mmd",
+	FormatPNG:     "png",
+}
+
+// RenderGraph renders trees in the format. Nodes with unknown prices are highlighted. PNG images are returned as
+// strings of their bytes, the same way exported files are sent through the processor.
+func RenderGraph(format string, trees []*TreeNode, lang string) (string, error) {
+	switch format {
+	case FormatDOT:
+		return RenderDOT(trees, lang), nil
+	case FormatMermaid:
+		return RenderMermaid(trees, lang), nil
+	case FormatPNG:
+		data, err := RenderPNG(trees)
+		return string(data), err
+	}
+	return "", fmt.Errorf("Unknown graph format: %v", format)
+}
+
+// RenderDOT renders trees as a Graphviz digraph. Every tree is a cluster.
+func RenderDOT(trees []*TreeNode, lang string) string {
+	var b strings.Builder
+	b.WriteString("digraph crafts {\n\tnode [shape=box];\n")
+
+	id := 0
+	for i, root := range trees {
+		fmt.Fprintf(&b, "\tsubgraph cluster_%v {\n\t\tlabel=\"%v\";\n", i, dotEscape(tr(lang, "craft."+root.Craft.String())))
+		walkTree(root, &id, func(n *TreeNode, nid int, parent int) {
+			attrs := ""
+			if len(n.Cost.NAReasons) > 0 {
+				attrs = ", color=red, fontcolor=red"
+			}
+			fmt.Fprintf(&b, "\t\tn%v [label=\"%v\"%v];\n", nid, dotEscape(strings.Join(nodeLabel(n, lang), "\n")), attrs)
+			if parent >= 0 {
+				fmt.Fprintf(&b, "\t\tn%v -> n%v;\n", parent, nid)
+			}
+		})
+		b.WriteString("\t}\n")
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// RenderMermaid renders trees as a Mermaid flowchart.
+func RenderMermaid(trees []*TreeNode, lang string) string {
+	var b strings.Builder
+	b.WriteString("graph TD\n\tclassDef na stroke:#d33,color:#d33;\n")
+
+	id := 0
+	for _, root := range trees {
+		walkTree(root, &id, func(n *TreeNode, nid int, parent int) {
+			fmt.Fprintf(&b, "\tn%v[\"%v\"]\n", nid, mermaidEscape(strings.Join(nodeLabel(n, lang), "<br/>")))
+			if len(n.Cost.NAReasons) > 0 {
+				fmt.Fprintf(&b, "\tclass n%v na\n", nid)
+			}
+			if parent >= 0 {
+				fmt.Fprintf(&b, "\tn%v --> n%v\n", parent, nid)
+			}
+		})
+	}
+
+	return b.String()
+}
+
+// walkTree visits nodes depth first, numbering them from *id on.
+func walkTree(n *TreeNode, id *int, visit func(n *TreeNode, nid int, parent int)) {
+	var walk func(n *TreeNode, parent int)
+	walk = func(n *TreeNode, parent int) {
+		nid := *id
+		*id += 1
+		visit(n, nid, parent)
+		for _, child := range n.Children {
+			walk(child, nid)
+		}
+	}
+	walk(n, -1)
+}
+
+// nodeLabel returns lines describing the node: name and quantity, the recipe if it is crafted and the cost.
+func nodeLabel(n *TreeNode, lang string) []string {
+	rv := []string{fmt.Sprintf("%v x %v", n.Quantity, n.Name)}
+	if n.Recipe != nil {
+		rv = append(rv, tr(lang, "tree.craft", tr(lang, "craft."+n.Craft.String()), n.Recipe.Level, n.Crafts))
+	}
+
+	cost := fmt.Sprint(n.Cost.Value)
+	switch {
+	case len(n.Cost.NAReasons) > 0 && n.Cost.Value == 0:
+		cost = "N/A"
+	case len(n.Cost.NAReasons) > 0:
+		cost += " + N/A"
+	}
+	return append(rv, cost)
+}
+
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
+
+// GraphFile returns the file name for the rendered graph of the item.
+func GraphFile(item string, format string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return '_'
+	}, strings.TrimSpace(item))
+	return name + "." + GraphExt[format]
+}
@@ -0,0 +1,87 @@
+package input
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/mebaranov/aioncraft/database"
+	"github.com/mebaranov/aioncraft/utility"
+)
+
+var update = flag.Bool("update", false, "Update golden files")
+
+func loadDatabase(t *testing.T) *database.Database {
+	data, err := ioutil.ReadFile("../data/database.json")
+	if err != nil {
+		t.Fatalf("Could not read database: %v", err)
+	}
+	db, err := database.NewFromJson(data)
+	if err != nil {
+		t.Fatalf("Could not unmarshal database: %v", err)
+	}
+	return db
+}
+
+func TestHowGolden(t *testing.T) {
+	tests := []struct {
+		name string
+		race database.Race
+		item string
+	}{
+		{"brogans", database.Elyos, "Expert Durable Adamantium Brogans"},
+		{"greatsword", database.Elyos, "Eremitia's Noble Greatsword"},
+		{"necklace", database.Asmodian, "Agehia's Noble Necklace"},
+		{"elemental_water", database.Elyos, "Lesser Elemental Water"},
+		{"not_found", database.Elyos, "No Such Item"},
+	}
+
+	p := NewProcessor(loadDatabase(t))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := p.Help(Command{Action: Help, Race: tt.race, Item: tt.item, Lang: database.DefaultLocale})
+
+			// The output must not depend on map order
+			for i := 0; i < 5; i++ {
+				if again := p.Help(Command{Action: Help, Race: tt.race, Item: tt.item, Lang: database.DefaultLocale}); again != got {
+					t.Fatalf("Help() is not deterministic:\n%v\n---\n%v", got, again)
+				}
+			}
+
+			golden := filepath.Join("testdata", "how", tt.name+".golden")
+			if *update {
+				if err := ioutil.WriteFile(golden, []byte(got), 0666); err != nil {
+					t.Fatalf("Could not update golden file: %v", err)
+				}
+			}
+			want, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("Could not read golden file: %v", err)
+			}
+			if got != string(want) {
+				t.Errorf("Help() = \n%v\nwant\n%v", got, string(want))
+			}
+		})
+	}
+}
+
+// TestGatherIngridientsShared checks that an item used by several crafts is counted before its ingredients:
+// A = B + C, B = 2 C, C = 3 D. Three C are crafted, so nine D are bought and C is crafted before B.
+func TestGatherIngridientsShared(t *testing.T) {
+	db := database.New()
+	for _, id := range []string{"A", "B", "C", "D"} {
+		db.Items[database.Elyos][id] = &database.Item{ID: id, Name: "Item " + id, Price: utility.NewInt(0, id)}
+	}
+	db.Items[database.Elyos]["D"].Price = &utility.TheInt{Value: 10}
+	recs := db.Recipes[database.Elyos][database.Alchemy]
+	recs["rA"] = &database.Recipe{ID: "rA", ItemID: "A", Count: 1, Items: map[string]int{"B": 1, "C": 1}}
+	recs["rB"] = &database.Recipe{ID: "rB", ItemID: "B", Count: 1, Items: map[string]int{"C": 2}}
+	recs["rC"] = &database.Recipe{ID: "rC", ItemID: "C", Count: 1, Items: map[string]int{"D": 3}}
+
+	got := NewProcessor(db).gatherIngridients(database.Elyos, database.Alchemy, "rA", nil, database.DefaultLocale)
+	want := "First you buy: \n\t9 x Item D, for 10 each, \nThen you craft: --> Item C (3) --> Item B (1) --> Item A (1) \n"
+	if got != want {
+		t.Errorf("gatherIngridients() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,205 @@
+package input
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+
+	"github.com/mebaranov/aioncraft/database"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	// treeImageMaxNodes and treeImageMaxSide keep images readable in Discord. Larger trees are sent as text.
+	treeImageMaxNodes = 80
+	treeImageMaxSide  = 4000
+
+	imageMargin = 10
+	boxPadding  = 5
+	boxLines    = 3
+	lineHeight  = 15
+	charWidth   = 7
+	hGap        = 14
+	vGap        = 28
+)
+
+var errTreeTooLarge = errors.New("The tree is too large for an image")
+
+var (
+	colorBackground = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	colorCrafted    = color.RGBA{0xe8, 0xf0, 0xfe, 0xff}
+	colorBought     = color.RGBA{0xf5, 0xf5, 0xf5, 0xff}
+	colorNA         = color.RGBA{0xfd, 0xe8, 0xe8, 0xff}
+	colorBorder     = color.RGBA{0x55, 0x55, 0x55, 0xff}
+	colorNABorder   = color.RGBA{0xdd, 0x33, 0x33, 0xff}
+	colorText       = color.RGBA{0x20, 0x20, 0x20, 0xff}
+)
+
+// box is a laid out tree node. X and y are the top left corner, width is the width of the whole subtree.
+type box struct {
+	node     *TreeNode
+	lines    []string
+	x, y     int
+	w, h     int
+	width    int
+	children []*box
+}
+
+// RenderPNG draws trees side by side. Only ASCII glyphs are available, so trees should be built with English names.
+// errTreeTooLarge is returned for trees which would not be readable.
+func RenderPNG(trees []*TreeNode) ([]byte, error) {
+	nodes, depth := 0, 0
+	roots := []*box{}
+	for _, tree := range trees {
+		roots = append(roots, layoutBox(tree, 0, &nodes, &depth))
+	}
+	if nodes > treeImageMaxNodes {
+		return nil, errTreeTooLarge
+	}
+
+	width := imageMargin * 2
+	for i, root := range roots {
+		if i > 0 {
+			width += hGap
+		}
+		width += root.width
+	}
+	boxHeight := boxLines*lineHeight + boxPadding*2
+	height := imageMargin*2 + (depth+1)*(boxHeight+vGap) - vGap
+	if width > treeImageMaxSide || height > treeImageMaxSide {
+		return nil, errTreeTooLarge
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(colorBackground), image.Point{}, draw.Src)
+
+	left := imageMargin
+	for _, root := range roots {
+		placeBox(root, left, 0)
+		drawBox(img, root)
+		left += root.width + hGap
+	}
+
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func layoutBox(n *TreeNode, level int, nodes *int, depth *int) *box {
+	*nodes += 1
+	if level > *depth {
+		*depth = level
+	}
+
+	b := &box{node: n, h: boxLines*lineHeight + boxPadding*2}
+	for _, line := range nodeLabel(n, database.DefaultLocale) {
+		line = asciiOnly(line)
+		b.lines = append(b.lines, line)
+		if w := len(line)*charWidth + boxPadding*2; w > b.w {
+			b.w = w
+		}
+	}
+
+	childrenWidth := 0
+	for i, child := range n.Children {
+		c := layoutBox(child, level+1, nodes, depth)
+		if i > 0 {
+			childrenWidth += hGap
+		}
+		childrenWidth += c.width
+		b.children = append(b.children, c)
+	}
+
+	b.width = b.w
+	if childrenWidth > b.width {
+		b.width = childrenWidth
+	}
+	return b
+}
+
+// placeBox centers the box over its children within the subtree starting at left.
+func placeBox(b *box, left int, level int) {
+	b.x = left + (b.width-b.w)/2
+	b.y = imageMargin + level*(b.h+vGap)
+
+	childrenWidth := -hGap
+	for _, c := range b.children {
+		childrenWidth += c.width + hGap
+	}
+	left += (b.width - childrenWidth) / 2
+	for _, c := range b.children {
+		placeBox(c, left, level+1)
+		left += c.width + hGap
+	}
+}
+
+func drawBox(img *image.RGBA, b *box) {
+	fill, border := colorBought, colorBorder
+	if b.node.Recipe != nil {
+		fill = colorCrafted
+	}
+	if len(b.node.Cost.NAReasons) > 0 {
+		fill, border = colorNA, colorNABorder
+	}
+
+	rect := image.Rect(b.x, b.y, b.x+b.w, b.y+b.h)
+	draw.Draw(img, rect, image.NewUniform(fill), image.Point{}, draw.Src)
+	hLine(img, rect.Min.X, rect.Max.X, rect.Min.Y, border)
+	hLine(img, rect.Min.X, rect.Max.X, rect.Max.Y-1, border)
+	vLine(img, rect.Min.X, rect.Min.Y, rect.Max.Y, border)
+	vLine(img, rect.Max.X-1, rect.Min.Y, rect.Max.Y, border)
+
+	d := &font.Drawer{Dst: img, Src: image.NewUniform(colorText), Face: basicfont.Face7x13}
+	for i, line := range b.lines {
+		if i == len(b.lines)-1 && len(b.node.Cost.NAReasons) > 0 {
+			d.Src = image.NewUniform(colorNABorder)
+		}
+		d.Dot = fixed.P(b.x+boxPadding, b.y+boxPadding+i*lineHeight+basicfont.Face7x13.Ascent)
+		d.DrawString(line)
+	}
+
+	// Edges go down from the parent, along the middle of the gap and down to the child.
+	fromX, fromY := b.x+b.w/2, b.y+b.h
+	midY := fromY + vGap/2
+	for _, c := range b.children {
+		toX := c.x + c.w/2
+		vLine(img, fromX, fromY, midY, colorBorder)
+		if toX < fromX {
+			hLine(img, toX, fromX+1, midY, colorBorder)
+		} else {
+			hLine(img, fromX, toX+1, midY, colorBorder)
+		}
+		vLine(img, toX, midY, c.y, colorBorder)
+		drawBox(img, c)
+	}
+}
+
+func hLine(img *image.RGBA, x1, x2, y int, c color.Color) {
+	for x := x1; x < x2; x++ {
+		img.Set(x, y, c)
+	}
+}
+
+func vLine(img *image.RGBA, x, y1, y2 int, c color.Color) {
+	for y := y1; y < y2; y++ {
+		img.Set(x, y, c)
+	}
+}
+
+// asciiOnly replaces characters the bitmap font has no glyphs for.
+func asciiOnly(s string) string {
+	rv := []rune(s)
+	for i, r := range rv {
+		if r < ' ' || r > '~' {
+			rv[i] = '?'
+		}
+	}
+	return string(rv)
+}
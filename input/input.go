@@ -1,6 +1,10 @@
 package input
 
-import "github.com/mebaranov/aioncraft/database"
+import (
+	"time"
+
+	"github.com/mebaranov/aioncraft/database"
+)
 
 type ActionType int
 
@@ -9,14 +13,40 @@ const (
 	Help
 	Set
 	Close
+	Import
+	Export
+	InventoryAdd
+	InventoryList
+	Patch
+	MissingCrafts
+	Uses
+	Level
+	Craftable
+	Profit
+	Graph
+	WatchCheck
+	DigestReport
+	StaleList
 )
 
 type Command struct {
-	Action ActionType
-	Race   database.Race
-	Item   string
-	Price  int
-	Out    chan string
+	Action    ActionType
+	Race      database.Race
+	Item      string
+	Price     int
+	Count     int
+	Craft     database.CraftType
+	From      int
+	To        int
+	Data      []byte
+	Book      map[string]int
+	Inventory map[string]int
+	Lang      string
+	Format    string
+	Watch     *Watch
+	Stale     *Staleness
+	Since     time.Time
+	Out       chan string
 }
 
 type InputController interface {
@@ -0,0 +1,119 @@
+package input
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/mebaranov/aioncraft/database"
+	"github.com/mebaranov/aioncraft/utility"
+)
+
+func (p *Processor) InventoryAdd(cmd Command) string {
+	it := p.findItem(cmd.Race, cmd.Item)
+	if it == nil {
+		return tr(cmd.Lang, "item.notfound", cmd.Item)
+	}
+
+	count := cmd.Inventory[it.ID] + cmd.Count
+	if count <= 0 {
+		delete(cmd.Inventory, it.ID)
+		return tr(cmd.Lang, "inv.removed", it.LocalName(cmd.Lang))
+	}
+
+	cmd.Inventory[it.ID] = count
+	return tr(cmd.Lang, "inv.count", count, it.LocalName(cmd.Lang))
+}
+
+func (p *Processor) InventoryList(cmd Command) string {
+	if len(cmd.Inventory) == 0 {
+		return tr(cmd.Lang, "inv.empty")
+	}
+
+	names := map[string]string{}
+	ids := []string{}
+	for id := range cmd.Inventory {
+		names[id] = id
+		if it, ok := p.db.Items[cmd.Race][id]; ok && it.Name != "" {
+			names[id] = it.LocalName(cmd.Lang)
+		}
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return names[ids[i]] < names[ids[j]]
+	})
+
+	rv := tr(cmd.Lang, "inv.list")
+	for _, id := range ids {
+		rv += fmt.Sprintf("\n\t%v x %v", cmd.Inventory[id], names[id])
+	}
+	return rv
+}
+
+// inventory handles '/c inv' commands. Inventory is personal, so it is kept in the user settings even when used in a guild.
+func (d *Discord) inventory(s *discordgo.Session, m *discordgo.MessageCreate, g *Guild, race database.Race, args string) {
+	params := strings.SplitN(strings.TrimSpace(args), " ", 2)
+	op := strings.ToLower(params[0])
+	switch op {
+	case "", "list":
+		g.cmdc <- Command{
+			Action:    InventoryList,
+			Race:      race,
+			Inventory: d.storedInventory(m.Author.ID, race),
+			Lang:      g.Language,
+			Out:       g.outc,
+		}
+		msg := <-g.outc
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+	case "clear":
+		d.mu.Lock()
+		if u, ok := d.Users[m.Author.ID]; ok && u.Inventory[race] != nil {
+			delete(u.Inventory, race)
+			d.SaveNeeded = true
+		}
+		d.mu.Unlock()
+		msg := tr(g.Language, "inv.cleared")
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+	case "add", "remove":
+		if len(params) != 2 {
+			msg := tr(g.Language, "inv.usage", g.prefix())
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+
+		item, count := params[1], 1
+		if idx := strings.LastIndex(item, " "); idx >= 0 {
+			if c, err := strconv.Atoi(item[idx+1:]); err == nil {
+				item, count = strings.TrimSpace(item[:idx]), c
+			}
+		}
+		if op == "remove" {
+			count = -count
+		}
+
+		// The processor changes a copy, which is merged into the kept inventory afterwards
+		before := d.storedInventory(m.Author.ID, race)
+		inv := copyCounts(before)
+		g.cmdc <- Command{
+			Action:    InventoryAdd,
+			Race:      race,
+			Item:      item,
+			Count:     count,
+			Inventory: inv,
+			Lang:      g.Language,
+			Out:       g.outc,
+		}
+		msg := <-g.outc
+
+		d.mu.Lock()
+		mergeCounts(d.user(m.Author.ID).inventory(race), before, inv)
+		d.SaveNeeded = true
+		d.mu.Unlock()
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+	default:
+		msg := tr(g.Language, "inv.unknown", params[0])
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+	}
+}
@@ -0,0 +1,166 @@
+package input
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mebaranov/aioncraft/database"
+	"github.com/mebaranov/aioncraft/utility"
+)
+
+const (
+	// levelBand is the amount of skill levels planned with a single recipe.
+	levelBand = 10
+	// greyRange is how many levels above its own a recipe still gives skill points.
+	greyRange = 40
+	// MaxSkill is the highest crafting skill level.
+	MaxSkill = 549
+)
+
+type levelStep struct {
+	from   int
+	to     int
+	rec    *database.Recipe
+	crafts int
+	each   *utility.TheInt
+}
+
+// Level plans leveling the craft from cmd.From to cmd.To. Every band of levels is leveled with the recipe which is
+// the cheapest to craft, counting the market value of its products. A craft is expected to give a skill point.
+func (p *Processor) Level(cmd Command) string {
+	if cmd.From < 0 || cmd.From >= cmd.To || cmd.To > MaxSkill {
+		return tr(cmd.Lang, "level.range", MaxSkill)
+	}
+
+	recs := []*database.Recipe{}
+	for _, rec := range p.db.Recipes[cmd.Race][cmd.Craft] {
+		if !rec.Removed {
+			recs = append(recs, rec)
+		}
+	}
+	sort.Slice(recs, func(i, j int) bool {
+		return recs[i].ID < recs[j].ID
+	})
+
+	pr := newPricing(cmd)
+	costs := map[string]*utility.TheInt{}
+	steps := []*levelStep{}
+	for from := cmd.From; from < cmd.To; from += levelBand {
+		to := from + levelBand
+		if to > cmd.To {
+			to = cmd.To
+		}
+
+		// Skill starts at 1, so level 1 recipes are available from 0 too
+		skill := from
+		if skill < 1 {
+			skill = 1
+		}
+		var best *database.Recipe
+		for _, rec := range recs {
+			if rec.Level > skill || rec.Level+greyRange < to {
+				continue
+			}
+			if _, ok := costs[rec.ID]; !ok {
+				costs[rec.ID] = p.netCost(cmd.Race, cmd.Craft, rec, pr)
+			}
+			if best == nil || cheaper(costs[rec.ID], costs[best.ID]) {
+				best = rec
+			}
+		}
+
+		if last := len(steps) - 1; last >= 0 && steps[last].rec == best {
+			steps[last].to = to
+			steps[last].crafts += to - from
+			continue
+		}
+		step := &levelStep{from: from, to: to, rec: best, crafts: to - from}
+		if best != nil {
+			step.each = costs[best.ID]
+		}
+		steps = append(steps, step)
+	}
+
+	rv := tr(cmd.Lang, "level.title", tr(cmd.Lang, "craft."+cmd.Craft.String()), cmd.From, cmd.To)
+	total := &utility.TheInt{}
+	for _, step := range steps {
+		if step.rec == nil {
+			rv += "\n" + tr(cmd.Lang, "level.gap", step.from, step.to)
+			continue
+		}
+
+		name := p.productName(cmd.Race, step.rec, cmd.Lang)
+		rv += "\n" + tr(cmd.Lang, "level.step", step.from, step.to, step.crafts, name, step.rec.Level, step.each.Value)
+		if len(step.each.NAReasons) > 0 {
+			rv += " + <N/A>"
+		}
+		total = total.Plus(step.each.Mul(step.crafts))
+	}
+
+	rv += "\n" + tr(cmd.Lang, "level.total", total.Value)
+	if len(total.NAReasons) > 0 {
+		rv += " + <N/A>\n\n" + tr(cmd.Lang, "price.improve") + "\n" + strings.Join(unique(total.NAReasons), ",")
+	}
+
+	return rv
+}
+
+// netCost is the price of a single craft minus the market value of its products.
+func (p *Processor) netCost(race database.Race, ct database.CraftType, rec *database.Recipe, pr *pricing) *utility.TheInt {
+	rv := p.priceByRecipe(race, ct, rec.ID, true, pr)
+	if value := p.itemPrice(race, rec.ItemID, pr); len(value.NAReasons) == 0 {
+		rv = rv.Plus(&utility.TheInt{Value: -value.Value * rec.Count})
+	}
+	return rv
+}
+
+// cheaper reports if price a is better than b. Fully known prices are preferred over partially known ones.
+func cheaper(a *utility.TheInt, b *utility.TheInt) bool {
+	if (len(a.NAReasons) == 0) != (len(b.NAReasons) == 0) {
+		return len(a.NAReasons) == 0
+	}
+	return a.Value < b.Value
+}
+
+func unique(list []string) []string {
+	seen := map[string]bool{}
+	rv := []string{}
+	for _, s := range list {
+		if !seen[s] {
+			seen[s] = true
+			rv = append(rv, s)
+		}
+	}
+	sort.Strings(rv)
+	return rv
+}
+
+// parseCraft finds the craft by its English name, alias or the name in the language.
+func parseCraft(lang string, name string) (database.CraftType, bool) {
+	if ct, ok := database.ParseCraft(name); ok {
+		return ct, true
+	}
+	for _, ct := range database.Crafts {
+		if strings.EqualFold(tr(lang, "craft."+ct.String()), strings.TrimSpace(name)) {
+			return ct, true
+		}
+	}
+	return 0, false
+}
+
+// parseLevelArgs splits '<craft> <from> <to>'. The craft name may have spaces.
+func parseLevelArgs(lang string, args string) (database.CraftType, int, int, bool) {
+	fields := strings.Fields(args)
+	if len(fields) < 3 {
+		return 0, 0, 0, false
+	}
+
+	from, err1 := strconv.Atoi(fields[len(fields)-2])
+	to, err2 := strconv.Atoi(fields[len(fields)-1])
+	ct, ok := parseCraft(lang, strings.Join(fields[:len(fields)-2], " "))
+	if err1 != nil || err2 != nil || !ok {
+		return 0, 0, 0, false
+	}
+	return ct, from, to, true
+}
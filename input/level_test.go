@@ -0,0 +1,50 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/mebaranov/aioncraft/database"
+	"github.com/mebaranov/aioncraft/utility"
+)
+
+// levelDatabase has alchemy recipes of level 1 and 10 made of 1 and 2 materials costing 10 each.
+func levelDatabase() *database.Database {
+	db := database.New()
+	items := db.Items[database.Elyos]
+	items["M"] = &database.Item{ID: "M", Name: "Material", Price: &utility.TheInt{Value: 10}}
+	items["P1"] = &database.Item{ID: "P1", Name: "Novice Potion", Price: utility.NewInt(0, "P1")}
+	items["P2"] = &database.Item{ID: "P2", Name: "Apprentice Potion", Price: utility.NewInt(0, "P2")}
+	recs := db.Recipes[database.Elyos][database.Alchemy]
+	recs["r1"] = &database.Recipe{ID: "r1", ItemID: "P1", Count: 1, Level: 1, Items: map[string]int{"M": 1}}
+	recs["r2"] = &database.Recipe{ID: "r2", ItemID: "P2", Count: 1, Level: 10, Items: map[string]int{"M": 2}}
+	return db
+}
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to int
+		want     string
+	}{
+		{"lowest band", 0, 50, "Leveling Alchemy from 0 to 50:\n" +
+			"0-40: 40 x Novice Potion (level 1), 10 each\n" +
+			"40-50: 10 x Apprentice Potion (level 10), 20 each\n" +
+			"Total: 600"},
+		{"first level", 1, 10, "Leveling Alchemy from 1 to 10:\n" +
+			"1-10: 9 x Novice Potion (level 1), 10 each\n" +
+			"Total: 90"},
+		{"grey", 60, 70, "Leveling Alchemy from 60 to 70:\n" +
+			"60-70: no recipes to level with\n" +
+			"Total: 0"},
+	}
+
+	p := NewProcessor(levelDatabase())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := p.Level(Command{Race: database.Elyos, Craft: database.Alchemy, From: tt.from, To: tt.to, Lang: database.DefaultLocale})
+			if got != tt.want {
+				t.Errorf("Level() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,1094 @@
+package input
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mebaranov/aioncraft/database"
+)
+
+// catalog keeps reply texts per language. Missing translations fall back to the default locale.
+var catalog = map[string]map[string]string{
+	database.DefaultLocale: {
+		"set.book":          "Price (%v) successfully set for item %v (%v) in your price book",
+		"set.done":          "Price (%v) successfully set for item %v (%v)",
+		"item.notfound":     "Item (%v) was not found.",
+		"price.craft":       "Type: %v (Level %v), Item: %v (x%v), Price: %v",
+		"price.base":        "Type: Base item, Item: %v, Price: %v",
+		"price.none":        "No items found following expression: \"%v\"",
+		"price.vendor":      "(NPC vendor)",
+		"price.improve":     "You can improve estimation quality and get rid of '<N/A>'s by adding the following prices:",
+		"how.manual":        "Type: %v (Level %v), Item: %v (x%v), Manual:\n%v",
+		"how.notfound":      "Item not found: \"%v\"",
+		"how.buy":           "First you buy: ",
+		"how.buyline":       "%v x %v, for %v each, ",
+		"how.craft":         "Then you craft: ",
+		"how.source":        "Design: %v",
+		"how.cost":          "cost: %v",
+		"how.kinah":         "%v kinah",
+		"how.ap":            "%v AP",
+		"how.tier":          "skill: %v",
+		"how.workorder":     "work order",
+		"import.parse":      "Could not parse the file: %v",
+		"import.empty":      "The file contains no prices",
+		"import.emptyname":  "Row %v: item name is empty",
+		"import.price":      "Row %v: wrong price for %v: \"%v\"",
+		"import.notfound":   "Row %v: item (%v) was not found",
+		"import.done":       "Imported %v of %v prices.",
+		"import.skipped":    "Following rows were skipped:",
+		"inv.removed":       "%v is removed from your inventory",
+		"inv.count":         "You have %v x %v now",
+		"inv.empty":         "Your inventory is empty",
+		"inv.list":          "Your inventory:",
+		"race.first":        "Select the race first (see %v help)",
+		"cmd.unknown":       "Command \"%v\" is not known",
+		"lang.set":          "Language is set to %v",
+		"lang.unknown":      "Language \"%v\" is not supported. Supported languages: %v",
+		"patch.none":        "No recipe changes are recorded yet",
+		"uses.none":         "%v is not used in any recipe",
+		"uses.title":        "%v is used in %v recipes ([depth]):",
+		"uses.line":         "[%v] %v, %v (level %v): %v, %v per craft",
+		"uses.more":         "...and %v more",
+		"level.title":       "Leveling %v from %v to %v:",
+		"level.step":        "%v-%v: %v x %v (level %v), %v each",
+		"level.gap":         "%v-%v: no recipes to level with",
+		"level.total":       "Total: %v",
+		"level.range":       "Levels must be from 0 to %v and the first one lower than the second one",
+		"level.usage":       "Use: level <craft> <from> <to>, e.g. level alchemy 1 100",
+		"help.missing":      "Warning: no recipes are loaded for %v. Prices and manuals of these crafts are unavailable.",
+		"craft.Alchemy":     "Alchemy",
+		"craft.Armorsmith":  "Armorsmith",
+		"craft.Cooking":     "Cooking",
+		"craft.Tailoring":   "Tailoring",
+		"craft.Weaponsmith": "Weaponsmith",
+		"craft.Handicraft":  "Handicraft",
+		"craft.Morph":       "Morph",
+
+		"craftable.none":     "Nothing can be crafted from these materials, even with a couple of purchases",
+		"craftable.ready":    "You can craft now:",
+		"craftable.line":     "%v x %v (%v, level %v)",
+		"craftable.near":     "One or two purchases away:",
+		"craftable.nearline": "%v (%v, level %v): buy %v for %v",
+
+		"profit.none":  "No profitable recipes found. Set sell prices of products and prices of materials first",
+		"profit.title": "Best use of %v kinah for %v:",
+		"profit.line":  "%v x %v (level %v): spend %v, profit %v",
+		"profit.total": "Spent: %v, income: %v, profit: %v",
+		"profit.buy":   "Materials to buy:",
+		"profit.usage": "Use: profit <budget> <craft> [max level], e.g. profit 5m alchemy 300",
+
+		"tree.craft": "%v, level %v, %v crafts",
+		"tree.usage": "Use: tree [dot|mermaid|png] <item name>, e.g. tree mermaid Steel Ingot",
+
+		"watch.usage":    "Use: %[1]v watch <item name> cost|margin|price <|> <value> [here|dm|#channel], e.g. %[1]v watch Steel Ingot margin>0 dm",
+		"watch.added":    "Watch #%v added: %v %v %v %v",
+		"watch.removed":  "Watch #%v removed",
+		"watch.notfound": "Watch not found: %v",
+		"watch.denied":   "Only the author or an administrator can remove watch #%v",
+		"watch.channel":  "Alerts can go only to channels of this server, <#%v> is not one of them",
+		"watch.none":     "No watches yet",
+		"watch.title":    "Watches:",
+		"watch.line":     "#%v: %v %v %v %v (%v), alerts to %v",
+		"watch.alert":    "Watch #%v: %v %v is %v now (%v %v)",
+		"watch.cost":     "craft cost",
+		"watch.margin":   "margin",
+		"watch.price":    "price",
+		"watch.dm":       "direct messages",
+
+		"digest.profit":      "Most profitable crafts:",
+		"digest.profitline":  "%v (%v, level %v): profit %v per craft, cost %v, sells for %v",
+		"digest.stale":       "Prices older than %v days:",
+		"digest.staleline":   "%v: %v, set %v",
+		"digest.nodate":      "before dates were tracked",
+		"digest.missing":     "Missing prices blocking most estimates:",
+		"digest.missingline": "%v: %v recipes",
+		"digest.none":        "No digests in this server",
+		"digest.title":       "Digests:",
+		"digest.line":        "%v in <#%v> at %v:00 UTC",
+		"digest.days":        "(older than %v days)",
+		"digest.usage":       "Use: %v digest [list|add <kind> [hour UTC] [days]|remove <kind>|now <kind>], kinds: %v",
+		"digest.admin":       "Only server administrators can configure digests",
+		"digest.added":       "The %v digest will be posted to this channel daily at %v:00 UTC",
+		"digest.removed":     "The %v digest is removed from this channel",
+		"digest.notfound":    "There is no %v digest in this channel",
+
+		"stale.flag":       "%v stale prices",
+		"stale.confidence": "confidence %v%%",
+		"stale.item":       "(stale, set %v)",
+		"stale.none":       "No stale prices",
+		"stale.title":      "Stale prices (%v):",
+		"stale.config":     "Prices are stale after %v days, by category: %v. Mode: %v",
+		"stale.usage":      "Use: %v stale [list|config|days <days> [category]|mode %v]",
+		"stale.admin":      "Only server administrators can configure stale prices",
+		"stale.days":       "Prices of %v are stale after %v days",
+		"stale.mode":       "Stale prices mode: %v",
+		"stale.other":      "other items",
+		"stale.more":       "...and %v more stale prices",
+
+		"cmd.guildonly":     "This command is not available in direct messages",
+		"attach.load":       "Could not load the attachment: %v",
+		"import.attach":     "Attach a CSV or JSON file with prices to the message",
+		"set.format":        "Wrong command format: Could not find item or price section",
+		"set.parse":         "Wrong command format: Could not parse price: %v",
+		"race.none":         "Race is not selected. Use '%v race <race> [server|channel|me]'",
+		"race.current":      "Current race is %v. Use '%v race <race> [server|channel|me]' to change it",
+		"race.wrong":        "Wrong race selected: %v",
+		"race.server":       "Race is set to %v",
+		"race.serverreset":  "Server race is reset",
+		"race.channel":      "Race for this channel is set to %v",
+		"race.channelreset": "Channel race is reset, server race is used",
+		"race.user":         "Your race is set to %v",
+		"race.userreset":    "Your race is reset, channel or server race is used",
+		"race.scope":        "Unknown race scope \"%v\". Use server, channel or me",
+		"inv.cleared":       "Your inventory is cleared",
+		"inv.usage":         "Wrong command format: use '%v inv add|remove <item name> <count>'",
+		"inv.unknown":       "Unknown inventory command \"%v\"",
+		"perm.denied":       "You are not allowed to use '%v' on this server",
+		"perm.readonly":     "(the server is in read-only mode)",
+		"perm.admin":        "Only server administrators can manage permissions",
+		"perm.usage":        "Wrong command format: use '%v perm grant|revoke <permission> <role>'",
+		"perm.unknown":      "Unknown permission \"%v\". Known are: %v",
+		"perm.roles":        "Could not load server roles: %v",
+		"perm.norole":       "Role \"%v\" was not found",
+		"perm.granted":      "Role %v can now use '%v'",
+		"perm.revoked":      "Role %v can not use '%v' anymore",
+		"perm.open":         "No roles left, so '%v' is open for everyone",
+		"perm.command":      "Unknown permission command \"%v\"",
+		"perm.mode":         "Read-only mode: %v",
+		"perm.admins":       "administrators only",
+		"perm.everyone":     "everyone",
+		"readonly.admin":    "Only server administrators can change read-only mode",
+		"readonly.on":       "Read-only mode is on. Only administrators and granted roles can change data",
+		"readonly.off":      "Read-only mode is off",
+		"readonly.usage":    "Read-only mode is %v. Use '%v readonly on|off' to change it",
+		"mode.on":           "on",
+		"mode.off":          "off",
+		"prefix.current":    "Current prefix is '%[1]v'. Use '%[1]v prefix <new prefix>' to change it",
+		"prefix.admin":      "Only server administrators can change the prefix",
+		"prefix.invalid":    "Prefix should be a single word up to 10 characters",
+		"prefix.set":        "Prefix is set to '%[1]v'. Try '%[1]v help'",
+		"alias.none":        "No aliases are configured",
+		"alias.list":        "Aliases: %v",
+		"alias.admin":       "Only server administrators can change aliases",
+		"alias.command":     "\"%v\" is a command already",
+		"alias.added":       "'%[1]v %[2]v' now works as '%[1]v %[3]v'",
+		"alias.unknown":     "Alias \"%v\" is not known",
+		"alias.removed":     "Alias \"%v\" is removed",
+		"alias.nopreset":    "No aliases for language \"%v\". Known languages: %v",
+		"alias.preset":      "Aliases are added: %v",
+		"alias.cleared":     "All aliases are removed",
+		"alias.usage":       "Wrong command format: use '%v alias [list|add <alias> <command>|remove <alias>|lang <%v>|clear]'",
+		"lang.admin":        "Only server administrators can change the language",
+		"announce.admin":    "Only server administrators can configure announcements",
+		"announce.here":     "Patch changes will be announced in this channel",
+		"announce.off":      "Patch announcements are off",
+		"announce.usage":    "Use '%v announce here|off' to configure the channel for patch announcements",
+		"announce.channel":  "Patch changes are announced in <#%v>.",
+		"patch.admin":       "Only server administrators can post recipe changes to the announcements channel",
+		"patch.nochan":      "The announcements channel is not configured. Use '%v announce here' first",
+		"patch.posted":      "Recipe changes are posted to <#%v>",
+
+		"help.title":      "Following commands are supported:",
+		"help.help":       "'/c help' - show this help",
+		"help.race":       "'/c race <race> [server|channel|me]' - set the race for the server (default), this channel or yourself. Use 'reset' to clear it.",
+		"help.set":        "'/c set <item name> <price>' - set a price for an item. Exact name is required.",
+		"help.price":      "'/c price <item name>' - shows a craft price estimate. You can use regular expressions for the name.",
+		"help.how":        "'/c how <item name>' - shows how to craft an item with the craft tree image. Exact name is required.",
+		"help.uses":       "'/c uses <item name>' - shows recipes using an item, directly or through other crafts.",
+		"help.level":      "'/c level <craft> <from> <to>' - plans the cheapest way to level a craft, e.g. '/c level alchemy 1 100'.",
+		"help.profit":     "'/c profit <budget> <craft> [max level]' - picks recipes to craft and sell for the most profit, e.g. '/c profit 5m alchemy 300'. Your inventory is used first.",
+		"help.tree":       "'/c tree [dot|mermaid|png] <item name>' - attaches the craft tree of the item as a Graphviz or Mermaid graph or an image. Mermaid is used by default.",
+		"help.import":     "'/c import' - set prices in bulk from an attached CSV (item,price) or JSON file.",
+		"help.export":     "'/c export' - download current prices as a CSV file.",
+		"help.perm":       "'/c perm [list|grant|revoke <permission> <role>]' - manage roles allowed to use set, race, import and change watches. Administrators only.",
+		"help.readonly":   "'/c readonly on|off' - allow changes only for administrators and granted roles. Administrators only.",
+		"help.prefix":     "'/c prefix <prefix>' - change the command prefix. Administrators only.",
+		"help.patch":      "'/c patch [announce]' - show recipe changes of the last game patch. Administrators can post them to the announcements channel with 'announce'.",
+		"help.announce":   "'/c announce here|off' - post recipe changes to this channel when the database is refreshed. Administrators only.",
+		"help.digest":     "'/c digest [list|add <kind> [hour UTC] [days]|remove <kind>|now <kind>]' - post daily reports to this channel: profit (most profitable crafts), stale (prices older than the days) or missing (prices blocking most estimates). Administrators only.",
+		"help.lang":       "'/c lang <language>' - change the language of replies (%v). Administrators only.",
+		"help.alias":      "'/c alias [list|add <alias> <command>|remove <alias>|lang <language>|clear]' - manage command aliases, e.g. '/c alias lang de'. Administrators only.",
+		"help.inv":        "'/c inv [add|remove <item name> <count>|clear]' - manage your personal inventory.",
+		"help.stale":      "'/c stale [list|config|days <days> [category]|mode flag|exclude|confidence]' - list prices which need updating or set after how many days prices of a category are stale and how estimates use them. Changing settings is for administrators only.",
+		"help.watch":      "'/c watch <item name> cost|margin|price <|> <value> [here|dm|#channel]' - alert when the value crosses the threshold, e.g. '/c watch Steel Ingot margin>0 dm'. Use 'watch list' and 'watch remove <id>' to manage watches.",
+		"help.craftable":  "'/c craftable [list]' - shows what you can craft from your inventory or a pasted list ('10 x Iron Ore' per line).",
+		"help.dm":         "You can also talk to me in direct messages. There prices you set are kept in your personal price book.",
+		"help.raceopt":    "Any command accepts '--race <race>' to use another race once, e.g. '/c price Gold Ingot --race asmo'.",
+		"help.norace":     "You should select a race using one of the following commands:\n\t'/c race Elyos' - for Elyos\n\t'/c race Asmodian' - for Asmodian.\n\nYou can change the race in the future. Add 'channel' or 'me' to set it only for this channel or yourself.",
+		"help.prefixnote": "Command prefix on this server is '%v'",
+		"help.invite":     "To add me to your server use this link: %v",
+		"help.source":     "My source code is there: %v",
+	},
+	"de": {
+		"set.book":          "Preis (%v) für %v (%v) in deinem Preisbuch gesetzt",
+		"set.done":          "Preis (%v) für %v (%v) erfolgreich gesetzt",
+		"item.notfound":     "Gegenstand (%v) wurde nicht gefunden.",
+		"price.craft":       "Typ: %v (Stufe %v), Gegenstand: %v (x%v), Preis: %v",
+		"price.base":        "Typ: Grundmaterial, Gegenstand: %v, Preis: %v",
+		"price.none":        "Keine Gegenstände für den Ausdruck gefunden: \"%v\"",
+		"price.vendor":      "(NPC-Händler)",
+		"price.improve":     "Du kannst die Schätzung verbessern und '<N/A>' loswerden, indem du folgende Preise setzt:",
+		"how.manual":        "Typ: %v (Stufe %v), Gegenstand: %v (x%v), Anleitung:\n%v",
+		"how.notfound":      "Gegenstand nicht gefunden: \"%v\"",
+		"how.buy":           "Zuerst kaufst du: ",
+		"how.buyline":       "%v x %v, für je %v, ",
+		"how.craft":         "Dann stellst du her: ",
+		"how.source":        "Entwurf: %v",
+		"how.cost":          "Kosten: %v",
+		"how.kinah":         "%v Kinah",
+		"how.ap":            "%v AP",
+		"how.tier":          "Fertigkeit: %v",
+		"how.workorder":     "Arbeitsauftrag",
+		"import.parse":      "Die Datei konnte nicht gelesen werden: %v",
+		"import.empty":      "Die Datei enthält keine Preise",
+		"import.emptyname":  "Zeile %v: Name des Gegenstands fehlt",
+		"import.price":      "Zeile %v: falscher Preis für %v: \"%v\"",
+		"import.notfound":   "Zeile %v: Gegenstand (%v) wurde nicht gefunden",
+		"import.done":       "%v von %v Preisen importiert.",
+		"import.skipped":    "Folgende Zeilen wurden übersprungen:",
+		"inv.removed":       "%v wurde aus deinem Inventar entfernt",
+		"inv.count":         "Du hast jetzt %v x %v",
+		"inv.empty":         "Dein Inventar ist leer",
+		"inv.list":          "Dein Inventar:",
+		"race.first":        "Wähle zuerst die Rasse (siehe %v help)",
+		"cmd.unknown":       "Befehl \"%v\" ist unbekannt",
+		"lang.set":          "Sprache ist auf %v gesetzt",
+		"lang.unknown":      "Sprache \"%v\" wird nicht unterstützt. Unterstützte Sprachen: %v",
+		"patch.none":        "Noch keine Rezeptänderungen erfasst",
+		"uses.none":         "%v wird in keinem Rezept verwendet",
+		"uses.title":        "%v wird in %v Rezepten verwendet ([Tiefe]):",
+		"uses.line":         "[%v] %v, %v (Stufe %v): %v, %v pro Herstellung",
+		"uses.more":         "...und %v weitere",
+		"level.title":       "Leveln von %v von %v bis %v:",
+		"level.step":        "%v-%v: %v x %v (Stufe %v), je %v",
+		"level.gap":         "%v-%v: keine Rezepte zum Leveln",
+		"level.total":       "Gesamt: %v",
+		"level.range":       "Stufen müssen zwischen 0 und %v liegen, die erste kleiner als die zweite",
+		"level.usage":       "Verwendung: level <Beruf> <von> <bis>, z. B. level alchemie 1 100",
+		"help.missing":      "Achtung: für %v sind keine Rezepte geladen. Preise und Anleitungen dieser Berufe fehlen.",
+		"craft.Alchemy":     "Alchemie",
+		"craft.Armorsmith":  "Rüstungsschmieden",
+		"craft.Cooking":     "Kochen",
+		"craft.Tailoring":   "Schneidern",
+		"craft.Weaponsmith": "Waffenschmieden",
+		"craft.Handicraft":  "Handwerk",
+		"craft.Morph":       "Verwandlung",
+
+		"craftable.none":     "Aus diesen Materialien lässt sich nichts herstellen, auch nicht mit ein paar Käufen",
+		"craftable.ready":    "Du kannst jetzt herstellen:",
+		"craftable.line":     "%v x %v (%v, Stufe %v)",
+		"craftable.near":     "Ein oder zwei Käufe entfernt:",
+		"craftable.nearline": "%v (%v, Stufe %v): kaufe %v für %v",
+
+		"profit.none":  "Keine profitablen Rezepte gefunden. Setze zuerst Verkaufspreise der Produkte und Materialpreise",
+		"profit.title": "Beste Verwendung von %v Kinah für %v:",
+		"profit.line":  "%v x %v (Stufe %v): Ausgaben %v, Gewinn %v",
+		"profit.total": "Ausgaben: %v, Einnahmen: %v, Gewinn: %v",
+		"profit.buy":   "Zu kaufende Materialien:",
+		"profit.usage": "Verwendung: profit <Budget> <Beruf> [max. Stufe], z. B. profit 5m alchemie 300",
+
+		"tree.craft": "%v, Stufe %v, %v Herstellungen",
+		"tree.usage": "Verwendung: tree [dot|mermaid|png] <Gegenstand>, z. B. tree mermaid Stahlbarren",
+
+		"watch.usage":    "Verwendung: %[1]v watch <Gegenstand> cost|margin|price <|> <Wert> [here|dm|#Kanal], z. B. %[1]v watch Stahlbarren margin>0 dm",
+		"watch.added":    "Beobachtung #%v hinzugefügt: %v %v %v %v",
+		"watch.removed":  "Beobachtung #%v entfernt",
+		"watch.notfound": "Beobachtung nicht gefunden: %v",
+		"watch.denied":   "Nur der Ersteller oder ein Administrator kann Beobachtung #%v entfernen",
+		"watch.channel":  "Alarme können nur an Kanäle dieses Servers gehen, <#%v> gehört nicht dazu",
+		"watch.none":     "Noch keine Beobachtungen",
+		"watch.title":    "Beobachtungen:",
+		"watch.line":     "#%v: %v %v %v %v (%v), Alarme an %v",
+		"watch.alert":    "Beobachtung #%v: %v %v ist jetzt %v (%v %v)",
+		"watch.cost":     "Herstellungskosten",
+		"watch.margin":   "Marge",
+		"watch.price":    "Preis",
+		"watch.dm":       "Direktnachrichten",
+
+		"digest.profit":      "Profitabelste Herstellungen:",
+		"digest.profitline":  "%v (%v, Stufe %v): Gewinn %v pro Herstellung, Kosten %v, Verkauf für %v",
+		"digest.stale":       "Preise älter als %v Tage:",
+		"digest.staleline":   "%v: %v, gesetzt %v",
+		"digest.nodate":      "bevor Daten erfasst wurden",
+		"digest.missing":     "Fehlende Preise, die die meisten Schätzungen blockieren:",
+		"digest.missingline": "%v: %v Rezepte",
+		"digest.none":        "Keine Berichte auf diesem Server",
+		"digest.title":       "Berichte:",
+		"digest.line":        "%v in <#%v> um %v:00 UTC",
+		"digest.days":        "(älter als %v Tage)",
+		"digest.usage":       "Verwendung: %v digest [list|add <Art> [Stunde UTC] [Tage]|remove <Art>|now <Art>], Arten: %v",
+		"digest.admin":       "Nur Serveradministratoren können Berichte konfigurieren",
+		"digest.added":       "Der Bericht %v wird täglich um %v:00 UTC in diesem Kanal gepostet",
+		"digest.removed":     "Der Bericht %v wurde aus diesem Kanal entfernt",
+		"digest.notfound":    "In diesem Kanal gibt es keinen Bericht %v",
+
+		"stale.flag":       "%v veraltete Preise",
+		"stale.confidence": "Zuverlässigkeit %v%%",
+		"stale.item":       "(veraltet, gesetzt %v)",
+		"stale.none":       "Keine veralteten Preise",
+		"stale.title":      "Veraltete Preise (%v):",
+		"stale.config":     "Preise veralten nach %v Tagen, nach Kategorie: %v. Modus: %v",
+		"stale.usage":      "Verwendung: %v stale [list|config|days <Tage> [Kategorie]|mode %v]",
+		"stale.admin":      "Nur Serveradministratoren können veraltete Preise konfigurieren",
+		"stale.days":       "Preise von %v veralten nach %v Tagen",
+		"stale.mode":       "Modus für veraltete Preise: %v",
+		"stale.other":      "sonstige Gegenstände",
+		"stale.more":       "...und %v weitere veraltete Preise",
+
+		"cmd.guildonly":     "Dieser Befehl ist in Direktnachrichten nicht verfügbar",
+		"attach.load":       "Der Anhang konnte nicht geladen werden: %v",
+		"import.attach":     "Hänge der Nachricht eine CSV- oder JSON-Datei mit Preisen an",
+		"set.format":        "Falsches Befehlsformat: Gegenstand oder Preis nicht gefunden",
+		"set.parse":         "Falsches Befehlsformat: Preis konnte nicht gelesen werden: %v",
+		"race.none":         "Keine Rasse gewählt. Verwende '%v race <Rasse> [server|channel|me]'",
+		"race.current":      "Aktuelle Rasse ist %v. Verwende '%v race <Rasse> [server|channel|me]', um sie zu ändern",
+		"race.wrong":        "Falsche Rasse gewählt: %v",
+		"race.server":       "Rasse ist auf %v gesetzt",
+		"race.serverreset":  "Serverrasse ist zurückgesetzt",
+		"race.channel":      "Rasse für diesen Kanal ist auf %v gesetzt",
+		"race.channelreset": "Kanalrasse ist zurückgesetzt, die Serverrasse wird verwendet",
+		"race.user":         "Deine Rasse ist auf %v gesetzt",
+		"race.userreset":    "Deine Rasse ist zurückgesetzt, die Kanal- oder Serverrasse wird verwendet",
+		"race.scope":        "Unbekannter Bereich \"%v\". Verwende server, channel oder me",
+		"inv.cleared":       "Dein Inventar ist geleert",
+		"inv.usage":         "Falsches Befehlsformat: verwende '%v inv add|remove <Gegenstand> <Anzahl>'",
+		"inv.unknown":       "Unbekannter Inventarbefehl \"%v\"",
+		"perm.denied":       "Du darfst '%v' auf diesem Server nicht verwenden",
+		"perm.readonly":     "(der Server ist schreibgeschützt)",
+		"perm.admin":        "Nur Serveradministratoren können Berechtigungen verwalten",
+		"perm.usage":        "Falsches Befehlsformat: verwende '%v perm grant|revoke <Berechtigung> <Rolle>'",
+		"perm.unknown":      "Unbekannte Berechtigung \"%v\". Bekannt sind: %v",
+		"perm.roles":        "Serverrollen konnten nicht geladen werden: %v",
+		"perm.norole":       "Rolle \"%v\" wurde nicht gefunden",
+		"perm.granted":      "Rolle %v darf jetzt '%v' verwenden",
+		"perm.revoked":      "Rolle %v darf '%v' nicht mehr verwenden",
+		"perm.open":         "Keine Rollen übrig, daher ist '%v' für alle offen",
+		"perm.command":      "Unbekannter Berechtigungsbefehl \"%v\"",
+		"perm.mode":         "Schreibschutz: %v",
+		"perm.admins":       "nur Administratoren",
+		"perm.everyone":     "alle",
+		"readonly.admin":    "Nur Serveradministratoren können den Schreibschutz ändern",
+		"readonly.on":       "Schreibschutz ist an. Nur Administratoren und berechtigte Rollen können Daten ändern",
+		"readonly.off":      "Schreibschutz ist aus",
+		"readonly.usage":    "Schreibschutz ist %v. Verwende '%v readonly on|off', um ihn zu ändern",
+		"mode.on":           "an",
+		"mode.off":          "aus",
+		"prefix.current":    "Aktuelles Präfix ist '%[1]v'. Verwende '%[1]v prefix <neues Präfix>', um es zu ändern",
+		"prefix.admin":      "Nur Serveradministratoren können das Präfix ändern",
+		"prefix.invalid":    "Das Präfix muss ein einzelnes Wort mit bis zu 10 Zeichen sein",
+		"prefix.set":        "Präfix ist auf '%[1]v' gesetzt. Probiere '%[1]v help'",
+		"alias.none":        "Keine Aliase konfiguriert",
+		"alias.list":        "Aliase: %v",
+		"alias.admin":       "Nur Serveradministratoren können Aliase ändern",
+		"alias.command":     "\"%v\" ist bereits ein Befehl",
+		"alias.added":       "'%[1]v %[2]v' funktioniert jetzt wie '%[1]v %[3]v'",
+		"alias.unknown":     "Alias \"%v\" ist unbekannt",
+		"alias.removed":     "Alias \"%v\" ist entfernt",
+		"alias.nopreset":    "Keine Aliase für die Sprache \"%v\". Bekannte Sprachen: %v",
+		"alias.preset":      "Aliase hinzugefügt: %v",
+		"alias.cleared":     "Alle Aliase sind entfernt",
+		"alias.usage":       "Falsches Befehlsformat: verwende '%v alias [list|add <Alias> <Befehl>|remove <Alias>|lang <%v>|clear]'",
+		"lang.admin":        "Nur Serveradministratoren können die Sprache ändern",
+		"announce.admin":    "Nur Serveradministratoren können Ankündigungen konfigurieren",
+		"announce.here":     "Patchänderungen werden in diesem Kanal angekündigt",
+		"announce.off":      "Patchankündigungen sind aus",
+		"announce.usage":    "Verwende '%v announce here|off', um den Kanal für Patchankündigungen festzulegen",
+		"announce.channel":  "Patchänderungen werden in <#%v> angekündigt.",
+		"patch.admin":       "Nur Serveradministratoren können Rezeptänderungen im Ankündigungskanal posten",
+		"patch.nochan":      "Kein Ankündigungskanal konfiguriert. Verwende zuerst '%v announce here'",
+		"patch.posted":      "Rezeptänderungen wurden in <#%v> gepostet",
+
+		"help.title":      "Folgende Befehle werden unterstützt:",
+		"help.help":       "'/c help' - zeigt diese Hilfe",
+		"help.race":       "'/c race <Rasse> [server|channel|me]' - setzt die Rasse für den Server (Standard), diesen Kanal oder dich. Mit 'reset' wird sie gelöscht.",
+		"help.set":        "'/c set <Gegenstand> <Preis>' - setzt den Preis eines Gegenstands. Der genaue Name ist nötig.",
+		"help.price":      "'/c price <Gegenstand>' - zeigt eine Schätzung der Herstellungskosten. Für den Namen sind reguläre Ausdrücke möglich.",
+		"help.how":        "'/c how <Gegenstand>' - zeigt, wie ein Gegenstand hergestellt wird, mit einem Bild des Herstellungsbaums. Der genaue Name ist nötig.",
+		"help.uses":       "'/c uses <Gegenstand>' - zeigt Rezepte, die einen Gegenstand direkt oder über andere Herstellungen verwenden.",
+		"help.level":      "'/c level <Beruf> <von> <bis>' - plant den günstigsten Weg, einen Beruf zu steigern, z. B. '/c level alchemie 1 100'.",
+		"help.profit":     "'/c profit <Budget> <Beruf> [max. Stufe]' - wählt Rezepte zum Herstellen und Verkaufen mit dem meisten Gewinn, z. B. '/c profit 5m alchemie 300'. Dein Inventar wird zuerst verwendet.",
+		"help.tree":       "'/c tree [dot|mermaid|png] <Gegenstand>' - hängt den Herstellungsbaum als Graphviz- oder Mermaid-Graph oder als Bild an. Standard ist Mermaid.",
+		"help.import":     "'/c import' - setzt Preise gesammelt aus einer angehängten CSV- (Gegenstand,Preis) oder JSON-Datei.",
+		"help.export":     "'/c export' - lädt die aktuellen Preise als CSV-Datei herunter.",
+		"help.perm":       "'/c perm [list|grant|revoke <Berechtigung> <Rolle>]' - verwaltet Rollen, die set, race und import verwenden und Beobachtungen ändern dürfen. Nur für Administratoren.",
+		"help.readonly":   "'/c readonly on|off' - erlaubt Änderungen nur Administratoren und berechtigten Rollen. Nur für Administratoren.",
+		"help.prefix":     "'/c prefix <Präfix>' - ändert das Befehlspräfix. Nur für Administratoren.",
+		"help.patch":      "'/c patch [announce]' - zeigt Rezeptänderungen des letzten Spielpatches. Administratoren können sie mit 'announce' im Ankündigungskanal posten.",
+		"help.announce":   "'/c announce here|off' - kündigt Rezeptänderungen in diesem Kanal an, wenn die Datenbank aktualisiert wird. Nur für Administratoren.",
+		"help.digest":     "'/c digest [list|add <Art> [Stunde UTC] [Tage]|remove <Art>|now <Art>]' - postet tägliche Berichte in diesen Kanal: profit (profitabelste Herstellungen), stale (Preise älter als die Tage) oder missing (Preise, die die meisten Schätzungen blockieren). Nur für Administratoren.",
+		"help.lang":       "'/c lang <Sprache>' - ändert die Sprache der Antworten (%v). Nur für Administratoren.",
+		"help.alias":      "'/c alias [list|add <Alias> <Befehl>|remove <Alias>|lang <Sprache>|clear]' - verwaltet Befehlsaliase, z. B. '/c alias lang de'. Nur für Administratoren.",
+		"help.inv":        "'/c inv [add|remove <Gegenstand> <Anzahl>|clear]' - verwaltet dein persönliches Inventar.",
+		"help.stale":      "'/c stale [list|config|days <Tage> [Kategorie]|mode flag|exclude|confidence]' - listet zu aktualisierende Preise auf oder legt fest, nach wie vielen Tagen Preise einer Kategorie veraltet sind und wie Schätzungen sie verwenden. Einstellungen ändern nur Administratoren.",
+		"help.watch":      "'/c watch <Gegenstand> cost|margin|price <|> <Wert> [here|dm|#Kanal]' - meldet, wenn der Wert die Schwelle überschreitet, z. B. '/c watch Stahlbarren margin>0 dm'. Mit 'watch list' und 'watch remove <ID>' verwaltest du Beobachtungen.",
+		"help.craftable":  "'/c craftable [list]' - zeigt, was du aus deinem Inventar oder einer eingefügten Liste ('10 x Eisenerz' pro Zeile) herstellen kannst.",
+		"help.dm":         "Du kannst mir auch Direktnachrichten schreiben. Dort gesetzte Preise kommen in dein persönliches Preisbuch.",
+		"help.raceopt":    "Jeder Befehl akzeptiert '--race <Rasse>', um einmalig eine andere Rasse zu verwenden, z. B. '/c price Goldbarren --race asmo'.",
+		"help.norace":     "Wähle eine Rasse mit einem der folgenden Befehle:\n\t'/c race Elyos' - für Elyos\n\t'/c race Asmodian' - für Asmodier.\n\nDu kannst die Rasse später ändern. Füge 'channel' oder 'me' hinzu, um sie nur für diesen Kanal oder dich zu setzen.",
+		"help.prefixnote": "Das Befehlspräfix auf diesem Server ist '%v'",
+		"help.invite":     "Um mich zu deinem Server hinzuzufügen, verwende diesen Link: %v",
+		"help.source":     "Mein Quellcode ist hier: %v",
+	},
+	"fr": {
+		"set.book":          "Prix (%v) défini pour %v (%v) dans votre carnet de prix",
+		"set.done":          "Prix (%v) défini pour %v (%v)",
+		"item.notfound":     "Objet (%v) introuvable.",
+		"price.craft":       "Type : %v (niveau %v), Objet : %v (x%v), Prix : %v",
+		"price.base":        "Type : matériau de base, Objet : %v, Prix : %v",
+		"price.none":        "Aucun objet ne correspond à l'expression : \"%v\"",
+		"price.vendor":      "(marchand PNJ)",
+		"price.improve":     "Vous pouvez améliorer l'estimation et supprimer les '<N/A>' en ajoutant les prix suivants :",
+		"how.manual":        "Type : %v (niveau %v), Objet : %v (x%v), Manuel :\n%v",
+		"how.notfound":      "Objet introuvable : \"%v\"",
+		"how.buy":           "D'abord, achetez : ",
+		"how.buyline":       "%v x %v, à %v l'unité, ",
+		"how.craft":         "Puis fabriquez : ",
+		"how.source":        "Plan : %v",
+		"how.cost":          "coût : %v",
+		"how.kinah":         "%v kinahs",
+		"how.ap":            "%v PA",
+		"how.tier":          "compétence : %v",
+		"how.workorder":     "commande de travail",
+		"import.parse":      "Impossible de lire le fichier : %v",
+		"import.empty":      "Le fichier ne contient aucun prix",
+		"import.emptyname":  "Ligne %v : nom de l'objet manquant",
+		"import.price":      "Ligne %v : prix incorrect pour %v : \"%v\"",
+		"import.notfound":   "Ligne %v : objet (%v) introuvable",
+		"import.done":       "%v prix importés sur %v.",
+		"import.skipped":    "Lignes ignorées :",
+		"inv.removed":       "%v a été retiré de votre inventaire",
+		"inv.count":         "Vous avez maintenant %v x %v",
+		"inv.empty":         "Votre inventaire est vide",
+		"inv.list":          "Votre inventaire :",
+		"race.first":        "Choisissez d'abord la race (voir %v help)",
+		"cmd.unknown":       "Commande \"%v\" inconnue",
+		"lang.set":          "La langue est définie sur %v",
+		"lang.unknown":      "La langue \"%v\" n'est pas prise en charge. Langues disponibles : %v",
+		"patch.none":        "Aucune modification de recette enregistrée",
+		"uses.none":         "%v n'est utilisé dans aucune recette",
+		"uses.title":        "%v est utilisé dans %v recettes ([profondeur]) :",
+		"uses.line":         "[%v] %v, %v (niveau %v) : %v, %v par fabrication",
+		"uses.more":         "...et %v de plus",
+		"level.title":       "Progression en %v de %v à %v :",
+		"level.step":        "%v-%v : %v x %v (niveau %v), %v l'unité",
+		"level.gap":         "%v-%v : aucune recette pour progresser",
+		"level.total":       "Total : %v",
+		"level.range":       "Les niveaux doivent être entre 0 et %v, le premier inférieur au second",
+		"level.usage":       "Utilisation : level <métier> <de> <à>, par ex. level alchimie 1 100",
+		"help.missing":      "Attention : aucune recette n'est chargée pour %v. Les prix et instructions de ces métiers sont indisponibles.",
+		"craft.Alchemy":     "Alchimie",
+		"craft.Armorsmith":  "Forge d'armures",
+		"craft.Cooking":     "Cuisine",
+		"craft.Tailoring":   "Couture",
+		"craft.Weaponsmith": "Forge d'armes",
+		"craft.Handicraft":  "Artisanat",
+		"craft.Morph":       "Transformation",
+
+		"craftable.none":     "Rien ne peut être fabriqué avec ces matériaux, même avec quelques achats",
+		"craftable.ready":    "Vous pouvez fabriquer maintenant :",
+		"craftable.line":     "%v x %v (%v, niveau %v)",
+		"craftable.near":     "À un ou deux achats près :",
+		"craftable.nearline": "%v (%v, niveau %v) : achetez %v pour %v",
+
+		"profit.none":  "Aucune recette rentable. Définissez d'abord les prix de vente des produits et des matériaux",
+		"profit.title": "Meilleur usage de %v kinahs en %v :",
+		"profit.line":  "%v x %v (niveau %v) : dépense %v, bénéfice %v",
+		"profit.total": "Dépensé : %v, revenu : %v, bénéfice : %v",
+		"profit.buy":   "Matériaux à acheter :",
+		"profit.usage": "Utilisation : profit <budget> <métier> [niveau max], par ex. profit 5m alchimie 300",
+
+		"tree.craft": "%v, niveau %v, %v fabrications",
+		"tree.usage": "Utilisation : tree [dot|mermaid|png] <objet>, par ex. tree mermaid Lingot d'acier",
+
+		"watch.usage":    "Utilisation : %[1]v watch <objet> cost|margin|price <|> <valeur> [here|dm|#salon], par ex. %[1]v watch Lingot d'acier margin>0 dm",
+		"watch.added":    "Surveillance n°%v ajoutée : %v %v %v %v",
+		"watch.removed":  "Surveillance n°%v supprimée",
+		"watch.notfound": "Surveillance introuvable : %v",
+		"watch.denied":   "Seul l'auteur ou un administrateur peut supprimer la surveillance n°%v",
+		"watch.channel":  "Les alertes ne peuvent aller que vers les salons de ce serveur, <#%v> n'en fait pas partie",
+		"watch.none":     "Aucune surveillance",
+		"watch.title":    "Surveillances :",
+		"watch.line":     "n°%v : %v %v %v %v (%v), alertes vers %v",
+		"watch.alert":    "Surveillance n°%v : %v %v vaut maintenant %v (%v %v)",
+		"watch.cost":     "coût de fabrication",
+		"watch.margin":   "marge",
+		"watch.price":    "prix",
+		"watch.dm":       "messages privés",
+
+		"digest.profit":      "Fabrications les plus rentables :",
+		"digest.profitline":  "%v (%v, niveau %v) : bénéfice %v par fabrication, coût %v, vendu %v",
+		"digest.stale":       "Prix de plus de %v jours :",
+		"digest.staleline":   "%v : %v, défini %v",
+		"digest.nodate":      "avant le suivi des dates",
+		"digest.missing":     "Prix manquants bloquant le plus d'estimations :",
+		"digest.missingline": "%v : %v recettes",
+		"digest.none":        "Aucun rapport sur ce serveur",
+		"digest.title":       "Rapports :",
+		"digest.line":        "%v dans <#%v> à %v:00 UTC",
+		"digest.days":        "(plus de %v jours)",
+		"digest.usage":       "Utilisation : %v digest [list|add <type> [heure UTC] [jours]|remove <type>|now <type>], types : %v",
+		"digest.admin":       "Seuls les administrateurs du serveur peuvent configurer les rapports",
+		"digest.added":       "Le rapport %v sera publié dans ce salon chaque jour à %v:00 UTC",
+		"digest.removed":     "Le rapport %v est retiré de ce salon",
+		"digest.notfound":    "Aucun rapport %v dans ce salon",
+
+		"stale.flag":       "%v prix périmés",
+		"stale.confidence": "fiabilité %v%%",
+		"stale.item":       "(périmé, défini %v)",
+		"stale.none":       "Aucun prix périmé",
+		"stale.title":      "Prix périmés (%v) :",
+		"stale.config":     "Les prix sont périmés après %v jours, par catégorie : %v. Mode : %v",
+		"stale.usage":      "Utilisation : %v stale [list|config|days <jours> [catégorie]|mode %v]",
+		"stale.admin":      "Seuls les administrateurs du serveur peuvent configurer les prix périmés",
+		"stale.days":       "Les prix de %v sont périmés après %v jours",
+		"stale.mode":       "Mode des prix périmés : %v",
+		"stale.other":      "autres objets",
+		"stale.more":       "...et %v autres prix périmés",
+
+		"cmd.guildonly":     "Cette commande n'est pas disponible en messages privés",
+		"attach.load":       "Impossible de charger la pièce jointe : %v",
+		"import.attach":     "Joignez au message un fichier CSV ou JSON contenant les prix",
+		"set.format":        "Format de commande incorrect : objet ou prix introuvable",
+		"set.parse":         "Format de commande incorrect : prix illisible : %v",
+		"race.none":         "Aucune race n'est choisie. Utilisez '%v race <race> [server|channel|me]'",
+		"race.current":      "La race actuelle est %v. Utilisez '%v race <race> [server|channel|me]' pour la changer",
+		"race.wrong":        "Race incorrecte : %v",
+		"race.server":       "La race est définie sur %v",
+		"race.serverreset":  "La race du serveur est réinitialisée",
+		"race.channel":      "La race de ce salon est définie sur %v",
+		"race.channelreset": "La race du salon est réinitialisée, celle du serveur est utilisée",
+		"race.user":         "Votre race est définie sur %v",
+		"race.userreset":    "Votre race est réinitialisée, celle du salon ou du serveur est utilisée",
+		"race.scope":        "Portée \"%v\" inconnue. Utilisez server, channel ou me",
+		"inv.cleared":       "Votre inventaire est vidé",
+		"inv.usage":         "Format de commande incorrect : utilisez '%v inv add|remove <objet> <quantité>'",
+		"inv.unknown":       "Commande d'inventaire \"%v\" inconnue",
+		"perm.denied":       "Vous n'êtes pas autorisé à utiliser '%v' sur ce serveur",
+		"perm.readonly":     "(le serveur est en lecture seule)",
+		"perm.admin":        "Seuls les administrateurs du serveur peuvent gérer les permissions",
+		"perm.usage":        "Format de commande incorrect : utilisez '%v perm grant|revoke <permission> <rôle>'",
+		"perm.unknown":      "Permission \"%v\" inconnue. Permissions connues : %v",
+		"perm.roles":        "Impossible de charger les rôles du serveur : %v",
+		"perm.norole":       "Rôle \"%v\" introuvable",
+		"perm.granted":      "Le rôle %v peut maintenant utiliser '%v'",
+		"perm.revoked":      "Le rôle %v ne peut plus utiliser '%v'",
+		"perm.open":         "Plus aucun rôle, '%v' est donc ouvert à tous",
+		"perm.command":      "Commande de permission \"%v\" inconnue",
+		"perm.mode":         "Lecture seule : %v",
+		"perm.admins":       "administrateurs uniquement",
+		"perm.everyone":     "tout le monde",
+		"readonly.admin":    "Seuls les administrateurs du serveur peuvent changer le mode lecture seule",
+		"readonly.on":       "Le mode lecture seule est activé. Seuls les administrateurs et les rôles autorisés peuvent modifier les données",
+		"readonly.off":      "Le mode lecture seule est désactivé",
+		"readonly.usage":    "Le mode lecture seule est %v. Utilisez '%v readonly on|off' pour le changer",
+		"mode.on":           "activé",
+		"mode.off":          "désactivé",
+		"prefix.current":    "Le préfixe actuel est '%[1]v'. Utilisez '%[1]v prefix <nouveau préfixe>' pour le changer",
+		"prefix.admin":      "Seuls les administrateurs du serveur peuvent changer le préfixe",
+		"prefix.invalid":    "Le préfixe doit être un seul mot de 10 caractères maximum",
+		"prefix.set":        "Le préfixe est défini sur '%[1]v'. Essayez '%[1]v help'",
+		"alias.none":        "Aucun alias n'est configuré",
+		"alias.list":        "Alias : %v",
+		"alias.admin":       "Seuls les administrateurs du serveur peuvent changer les alias",
+		"alias.command":     "\"%v\" est déjà une commande",
+		"alias.added":       "'%[1]v %[2]v' fonctionne maintenant comme '%[1]v %[3]v'",
+		"alias.unknown":     "Alias \"%v\" inconnu",
+		"alias.removed":     "L'alias \"%v\" est supprimé",
+		"alias.nopreset":    "Aucun alias pour la langue \"%v\". Langues connues : %v",
+		"alias.preset":      "Alias ajoutés : %v",
+		"alias.cleared":     "Tous les alias sont supprimés",
+		"alias.usage":       "Format de commande incorrect : utilisez '%v alias [list|add <alias> <commande>|remove <alias>|lang <%v>|clear]'",
+		"lang.admin":        "Seuls les administrateurs du serveur peuvent changer la langue",
+		"announce.admin":    "Seuls les administrateurs du serveur peuvent configurer les annonces",
+		"announce.here":     "Les changements des patchs seront annoncés dans ce salon",
+		"announce.off":      "Les annonces des patchs sont désactivées",
+		"announce.usage":    "Utilisez '%v announce here|off' pour configurer le salon des annonces de patch",
+		"announce.channel":  "Les changements des patchs sont annoncés dans <#%v>.",
+		"patch.admin":       "Seuls les administrateurs du serveur peuvent publier les changements de recettes dans le salon des annonces",
+		"patch.nochan":      "Le salon des annonces n'est pas configuré. Utilisez d'abord '%v announce here'",
+		"patch.posted":      "Les changements de recettes sont publiés dans <#%v>",
+
+		"help.title":      "Commandes disponibles :",
+		"help.help":       "'/c help' - affiche cette aide",
+		"help.race":       "'/c race <race> [server|channel|me]' - définit la race du serveur (par défaut), de ce salon ou la vôtre. Utilisez 'reset' pour l'effacer.",
+		"help.set":        "'/c set <nom de l'objet> <prix>' - définit le prix d'un objet. Le nom exact est requis.",
+		"help.price":      "'/c price <nom de l'objet>' - estime le coût de fabrication. Le nom accepte les expressions régulières.",
+		"help.how":        "'/c how <nom de l'objet>' - montre comment fabriquer un objet avec l'image de l'arbre de fabrication. Le nom exact est requis.",
+		"help.uses":       "'/c uses <nom de l'objet>' - montre les recettes utilisant un objet, directement ou via d'autres fabrications.",
+		"help.level":      "'/c level <métier> <de> <à>' - planifie la façon la moins chère de monter un métier, par ex. '/c level alchimie 1 100'.",
+		"help.profit":     "'/c profit <budget> <métier> [niveau max]' - choisit les recettes à fabriquer et vendre pour le plus de profit, par ex. '/c profit 5m alchimie 300'. Votre inventaire est utilisé en premier.",
+		"help.tree":       "'/c tree [dot|mermaid|png] <nom de l'objet>' - joint l'arbre de fabrication sous forme de graphe Graphviz ou Mermaid ou d'image. Mermaid par défaut.",
+		"help.import":     "'/c import' - définit des prix en masse depuis un fichier CSV (objet,prix) ou JSON joint.",
+		"help.export":     "'/c export' - télécharge les prix actuels en fichier CSV.",
+		"help.perm":       "'/c perm [list|grant|revoke <permission> <rôle>]' - gère les rôles autorisés à utiliser set, race et import et à modifier les alertes. Administrateurs uniquement.",
+		"help.readonly":   "'/c readonly on|off' - n'autorise les modifications qu'aux administrateurs et aux rôles autorisés. Administrateurs uniquement.",
+		"help.prefix":     "'/c prefix <préfixe>' - change le préfixe des commandes. Administrateurs uniquement.",
+		"help.patch":      "'/c patch [announce]' - montre les changements de recettes du dernier patch. Les administrateurs peuvent les publier dans le salon des annonces avec 'announce'.",
+		"help.announce":   "'/c announce here|off' - publie les changements de recettes dans ce salon quand la base est mise à jour. Administrateurs uniquement.",
+		"help.digest":     "'/c digest [list|add <type> [heure UTC] [jours]|remove <type>|now <type>]' - publie des rapports quotidiens dans ce salon : profit (fabrications les plus rentables), stale (prix plus anciens que les jours) ou missing (prix bloquant le plus d'estimations). Administrateurs uniquement.",
+		"help.lang":       "'/c lang <langue>' - change la langue des réponses (%v). Administrateurs uniquement.",
+		"help.alias":      "'/c alias [list|add <alias> <commande>|remove <alias>|lang <langue>|clear]' - gère les alias de commandes, par ex. '/c alias lang fr'. Administrateurs uniquement.",
+		"help.inv":        "'/c inv [add|remove <nom de l'objet> <quantité>|clear]' - gère votre inventaire personnel.",
+		"help.stale":      "'/c stale [list|config|days <jours> [catégorie]|mode flag|exclude|confidence]' - liste les prix à mettre à jour ou définit après combien de jours les prix d'une catégorie sont périmés et comment les estimations les utilisent. Seuls les administrateurs changent les réglages.",
+		"help.watch":      "'/c watch <nom de l'objet> cost|margin|price <|> <valeur> [here|dm|#salon]' - alerte quand la valeur franchit le seuil, par ex. '/c watch Lingot d'acier margin>0 dm'. Utilisez 'watch list' et 'watch remove <id>' pour gérer les alertes.",
+		"help.craftable":  "'/c craftable [list]' - montre ce que vous pouvez fabriquer avec votre inventaire ou une liste collée ('10 x Minerai de fer' par ligne).",
+		"help.dm":         "Vous pouvez aussi me parler en messages privés. Les prix que vous y définissez sont gardés dans votre carnet de prix personnel.",
+		"help.raceopt":    "Toute commande accepte '--race <race>' pour utiliser une autre race une fois, par ex. '/c price Lingot d'or --race asmo'.",
+		"help.norace":     "Choisissez une race avec l'une des commandes suivantes :\n\t'/c race Elyos' - pour les Elyséens\n\t'/c race Asmodian' - pour les Asmodiens.\n\nVous pourrez changer la race plus tard. Ajoutez 'channel' ou 'me' pour la définir seulement pour ce salon ou pour vous.",
+		"help.prefixnote": "Le préfixe des commandes sur ce serveur est '%v'",
+		"help.invite":     "Pour m'ajouter à votre serveur, utilisez ce lien : %v",
+		"help.source":     "Mon code source est ici : %v",
+	},
+	"ru": {
+		"set.book":          "Цена (%v) для %v (%v) сохранена в вашем прайс-листе",
+		"set.done":          "Цена (%v) для %v (%v) успешно установлена",
+		"item.notfound":     "Предмет (%v) не найден.",
+		"price.craft":       "Тип: %v (уровень %v), Предмет: %v (x%v), Цена: %v",
+		"price.base":        "Тип: базовый предмет, Предмет: %v, Цена: %v",
+		"price.none":        "Не найдено предметов по выражению: \"%v\"",
+		"price.vendor":      "(у торговца NPC)",
+		"price.improve":     "Чтобы улучшить оценку и избавиться от '<N/A>', добавьте цены следующих предметов:",
+		"how.manual":        "Тип: %v (уровень %v), Предмет: %v (x%v), Инструкция:\n%v",
+		"how.notfound":      "Предмет не найден: \"%v\"",
+		"how.buy":           "Сначала купите: ",
+		"how.buyline":       "%v x %v, по %v за штуку, ",
+		"how.craft":         "Затем создайте: ",
+		"how.source":        "Рецепт: %v",
+		"how.cost":          "цена: %v",
+		"how.kinah":         "%v кинар",
+		"how.ap":            "%v ОБ",
+		"how.tier":          "навык: %v",
+		"how.workorder":     "рабочий заказ",
+		"import.parse":      "Не удалось разобрать файл: %v",
+		"import.empty":      "В файле нет цен",
+		"import.emptyname":  "Строка %v: не указано название предмета",
+		"import.price":      "Строка %v: неверная цена для %v: \"%v\"",
+		"import.notfound":   "Строка %v: предмет (%v) не найден",
+		"import.done":       "Импортировано цен: %v из %v.",
+		"import.skipped":    "Пропущены строки:",
+		"inv.removed":       "%v удален из инвентаря",
+		"inv.count":         "Теперь у вас %v x %v",
+		"inv.empty":         "Ваш инвентарь пуст",
+		"inv.list":          "Ваш инвентарь:",
+		"race.first":        "Сначала выберите расу (см. %v help)",
+		"cmd.unknown":       "Команда \"%v\" неизвестна",
+		"lang.set":          "Выбран язык: %v",
+		"lang.unknown":      "Язык \"%v\" не поддерживается. Доступные языки: %v",
+		"patch.none":        "Изменений рецептов пока нет",
+		"uses.none":         "%v не используется ни в одном рецепте",
+		"uses.title":        "%v используется в %v рецептах ([глубина]):",
+		"uses.line":         "[%v] %v, %v (уровень %v): %v, %v за создание",
+		"uses.more":         "...и еще %v",
+		"level.title":       "Прокачка %v с %v до %v:",
+		"level.step":        "%v-%v: %v x %v (уровень %v), по %v",
+		"level.gap":         "%v-%v: нет рецептов для прокачки",
+		"level.total":       "Итого: %v",
+		"level.range":       "Уровни должны быть от 0 до %v, первый меньше второго",
+		"level.usage":       "Использование: level <профессия> <от> <до>, например level алхимия 1 100",
+		"help.missing":      "Внимание: рецепты для %v не загружены. Цены и инструкции для этих профессий недоступны.",
+		"craft.Alchemy":     "Алхимия",
+		"craft.Armorsmith":  "Изготовление доспехов",
+		"craft.Cooking":     "Кулинария",
+		"craft.Tailoring":   "Портняжное дело",
+		"craft.Weaponsmith": "Кузнечное дело",
+		"craft.Handicraft":  "Ремесло",
+		"craft.Morph":       "Трансформация",
+
+		"craftable.none":     "Из этих материалов ничего нельзя создать, даже докупив пару предметов",
+		"craftable.ready":    "Можно создать сейчас:",
+		"craftable.line":     "%v x %v (%v, уровень %v)",
+		"craftable.near":     "Не хватает одной-двух покупок:",
+		"craftable.nearline": "%v (%v, уровень %v): купите %v за %v",
+
+		"profit.none":  "Выгодных рецептов не найдено. Сначала задайте цены продажи товаров и цены материалов",
+		"profit.title": "Лучшее использование %v кинар для %v:",
+		"profit.line":  "%v x %v (уровень %v): затраты %v, прибыль %v",
+		"profit.total": "Затраты: %v, доход: %v, прибыль: %v",
+		"profit.buy":   "Купить материалы:",
+		"profit.usage": "Использование: profit <бюджет> <профессия> [макс. уровень], например profit 5m алхимия 300",
+
+		"tree.craft": "%v, уровень %v, %v крафтов",
+		"tree.usage": "Использование: tree [dot|mermaid|png] <предмет>, например tree mermaid Стальной слиток",
+
+		"watch.usage":    "Использование: %[1]v watch <предмет> cost|margin|price <|> <значение> [here|dm|#канал], например %[1]v watch Стальной слиток margin>0 dm",
+		"watch.added":    "Отслеживание #%v добавлено: %v %v %v %v",
+		"watch.removed":  "Отслеживание #%v удалено",
+		"watch.notfound": "Отслеживание не найдено: %v",
+		"watch.denied":   "Удалить отслеживание #%v может только автор или администратор",
+		"watch.channel":  "Оповещения можно отправлять только в каналы этого сервера, <#%v> к ним не относится",
+		"watch.none":     "Отслеживаний пока нет",
+		"watch.title":    "Отслеживания:",
+		"watch.line":     "#%v: %v %v %v %v (%v), оповещения в %v",
+		"watch.alert":    "Отслеживание #%v: %v %v теперь %v (%v %v)",
+		"watch.cost":     "стоимость крафта",
+		"watch.margin":   "маржа",
+		"watch.price":    "цена",
+		"watch.dm":       "личные сообщения",
+
+		"digest.profit":      "Самые выгодные крафты:",
+		"digest.profitline":  "%v (%v, уровень %v): прибыль %v за крафт, затраты %v, продажа за %v",
+		"digest.stale":       "Цены старше %v дней:",
+		"digest.staleline":   "%v: %v, задана %v",
+		"digest.nodate":      "до учёта дат",
+		"digest.missing":     "Недостающие цены, блокирующие больше всего оценок:",
+		"digest.missingline": "%v: %v рецептов",
+		"digest.none":        "На этом сервере нет сводок",
+		"digest.title":       "Сводки:",
+		"digest.line":        "%v в <#%v> в %v:00 UTC",
+		"digest.days":        "(старше %v дней)",
+		"digest.usage":       "Использование: %v digest [list|add <вид> [час UTC] [дни]|remove <вид>|now <вид>], виды: %v",
+		"digest.admin":       "Настраивать сводки могут только администраторы сервера",
+		"digest.added":       "Сводка %v будет публиковаться в этом канале ежедневно в %v:00 UTC",
+		"digest.removed":     "Сводка %v удалена из этого канала",
+		"digest.notfound":    "В этом канале нет сводки %v",
+
+		"stale.flag":       "устаревших цен: %v",
+		"stale.confidence": "достоверность %v%%",
+		"stale.item":       "(устарела, задана %v)",
+		"stale.none":       "Устаревших цен нет",
+		"stale.title":      "Устаревшие цены (%v):",
+		"stale.config":     "Цены устаревают через %v дней, по категориям: %v. Режим: %v",
+		"stale.usage":      "Использование: %v stale [list|config|days <дни> [категория]|mode %v]",
+		"stale.admin":      "Настраивать устаревание цен могут только администраторы сервера",
+		"stale.days":       "Цены %v устаревают через %v дней",
+		"stale.mode":       "Режим устаревших цен: %v",
+		"stale.other":      "остальных предметов",
+		"stale.more":       "...и ещё %v устаревших цен",
+
+		"cmd.guildonly":     "Эта команда недоступна в личных сообщениях",
+		"attach.load":       "Не удалось загрузить вложение: %v",
+		"import.attach":     "Прикрепите к сообщению CSV или JSON файл с ценами",
+		"set.format":        "Неверный формат команды: не найден предмет или цена",
+		"set.parse":         "Неверный формат команды: не удалось прочитать цену: %v",
+		"race.none":         "Раса не выбрана. Используйте '%v race <раса> [server|channel|me]'",
+		"race.current":      "Текущая раса: %v. Используйте '%v race <раса> [server|channel|me]', чтобы изменить её",
+		"race.wrong":        "Выбрана неверная раса: %v",
+		"race.server":       "Выбрана раса: %v",
+		"race.serverreset":  "Раса сервера сброшена",
+		"race.channel":      "Раса для этого канала: %v",
+		"race.channelreset": "Раса канала сброшена, используется раса сервера",
+		"race.user":         "Ваша раса: %v",
+		"race.userreset":    "Ваша раса сброшена, используется раса канала или сервера",
+		"race.scope":        "Неизвестная область \"%v\". Используйте server, channel или me",
+		"inv.cleared":       "Ваш инвентарь очищен",
+		"inv.usage":         "Неверный формат команды: используйте '%v inv add|remove <предмет> <количество>'",
+		"inv.unknown":       "Неизвестная команда инвентаря \"%v\"",
+		"perm.denied":       "Вам нельзя использовать '%v' на этом сервере",
+		"perm.readonly":     "(сервер в режиме только для чтения)",
+		"perm.admin":        "Управлять разрешениями могут только администраторы сервера",
+		"perm.usage":        "Неверный формат команды: используйте '%v perm grant|revoke <разрешение> <роль>'",
+		"perm.unknown":      "Неизвестное разрешение \"%v\". Известные: %v",
+		"perm.roles":        "Не удалось загрузить роли сервера: %v",
+		"perm.norole":       "Роль \"%v\" не найдена",
+		"perm.granted":      "Роль %v теперь может использовать '%v'",
+		"perm.revoked":      "Роль %v больше не может использовать '%v'",
+		"perm.open":         "Ролей не осталось, поэтому '%v' доступна всем",
+		"perm.command":      "Неизвестная команда разрешений \"%v\"",
+		"perm.mode":         "Режим только для чтения: %v",
+		"perm.admins":       "только администраторы",
+		"perm.everyone":     "все",
+		"readonly.admin":    "Изменять режим только для чтения могут только администраторы сервера",
+		"readonly.on":       "Режим только для чтения включён. Изменять данные могут только администраторы и разрешённые роли",
+		"readonly.off":      "Режим только для чтения выключен",
+		"readonly.usage":    "Режим только для чтения %v. Используйте '%v readonly on|off', чтобы изменить его",
+		"mode.on":           "включён",
+		"mode.off":          "выключен",
+		"prefix.current":    "Текущий префикс: '%[1]v'. Используйте '%[1]v prefix <новый префикс>', чтобы изменить его",
+		"prefix.admin":      "Изменять префикс могут только администраторы сервера",
+		"prefix.invalid":    "Префикс должен быть одним словом длиной до 10 символов",
+		"prefix.set":        "Установлен префикс '%[1]v'. Попробуйте '%[1]v help'",
+		"alias.none":        "Псевдонимы не настроены",
+		"alias.list":        "Псевдонимы: %v",
+		"alias.admin":       "Изменять псевдонимы могут только администраторы сервера",
+		"alias.command":     "\"%v\" уже является командой",
+		"alias.added":       "'%[1]v %[2]v' теперь работает как '%[1]v %[3]v'",
+		"alias.unknown":     "Псевдоним \"%v\" неизвестен",
+		"alias.removed":     "Псевдоним \"%v\" удалён",
+		"alias.nopreset":    "Нет псевдонимов для языка \"%v\". Известные языки: %v",
+		"alias.preset":      "Добавлены псевдонимы: %v",
+		"alias.cleared":     "Все псевдонимы удалены",
+		"alias.usage":       "Неверный формат команды: используйте '%v alias [list|add <псевдоним> <команда>|remove <псевдоним>|lang <%v>|clear]'",
+		"lang.admin":        "Изменять язык могут только администраторы сервера",
+		"announce.admin":    "Настраивать объявления могут только администраторы сервера",
+		"announce.here":     "Изменения патчей будут объявляться в этом канале",
+		"announce.off":      "Объявления о патчах выключены",
+		"announce.usage":    "Используйте '%v announce here|off', чтобы выбрать канал для объявлений о патчах",
+		"announce.channel":  "Изменения патчей объявляются в <#%v>.",
+		"patch.admin":       "Публиковать изменения рецептов в канале объявлений могут только администраторы сервера",
+		"patch.nochan":      "Канал объявлений не настроен. Сначала используйте '%v announce here'",
+		"patch.posted":      "Изменения рецептов опубликованы в <#%v>",
+
+		"help.title":      "Поддерживаются следующие команды:",
+		"help.help":       "'/c help' - показать эту справку",
+		"help.race":       "'/c race <раса> [server|channel|me]' - выбрать расу для сервера (по умолчанию), этого канала или себя. 'reset' сбрасывает её.",
+		"help.set":        "'/c set <название предмета> <цена>' - установить цену предмета. Нужно точное название.",
+		"help.price":      "'/c price <название предмета>' - оценка стоимости крафта. В названии можно использовать регулярные выражения.",
+		"help.how":        "'/c how <название предмета>' - как скрафтить предмет, с изображением дерева крафта. Нужно точное название.",
+		"help.uses":       "'/c uses <название предмета>' - рецепты, использующие предмет напрямую или через другие крафты.",
+		"help.level":      "'/c level <профессия> <от> <до>' - самый дешёвый способ прокачать профессию, например '/c level алхимия 1 100'.",
+		"help.profit":     "'/c profit <бюджет> <профессия> [макс. уровень]' - выбирает рецепты для крафта и продажи с наибольшей прибылью, например '/c profit 5m алхимия 300'. Сначала используется ваш инвентарь.",
+		"help.tree":       "'/c tree [dot|mermaid|png] <название предмета>' - прикрепляет дерево крафта в виде графа Graphviz или Mermaid или изображения. По умолчанию Mermaid.",
+		"help.import":     "'/c import' - установить цены списком из прикреплённого CSV (предмет,цена) или JSON файла.",
+		"help.export":     "'/c export' - скачать текущие цены CSV файлом.",
+		"help.perm":       "'/c perm [list|grant|revoke <разрешение> <роль>]' - управлять ролями, которым можно использовать set, race и import и менять наблюдения. Только для администраторов.",
+		"help.readonly":   "'/c readonly on|off' - разрешить изменения только администраторам и разрешённым ролям. Только для администраторов.",
+		"help.prefix":     "'/c prefix <префикс>' - изменить префикс команд. Только для администраторов.",
+		"help.patch":      "'/c patch [announce]' - показать изменения рецептов последнего патча. Администраторы могут опубликовать их в канале объявлений с 'announce'.",
+		"help.announce":   "'/c announce here|off' - публиковать изменения рецептов в этом канале при обновлении базы. Только для администраторов.",
+		"help.digest":     "'/c digest [list|add <тип> [час UTC] [дни]|remove <тип>|now <тип>]' - публиковать ежедневные сводки в этом канале: profit (самые прибыльные крафты), stale (цены старше заданных дней) или missing (цены, мешающие большинству оценок). Только для администраторов.",
+		"help.lang":       "'/c lang <язык>' - изменить язык ответов (%v). Только для администраторов.",
+		"help.alias":      "'/c alias [list|add <псевдоним> <команда>|remove <псевдоним>|lang <язык>|clear]' - управлять псевдонимами команд, например '/c alias lang ru'. Только для администраторов.",
+		"help.inv":        "'/c inv [add|remove <название предмета> <количество>|clear]' - управлять личным инвентарём.",
+		"help.stale":      "'/c stale [list|config|days <дни> [категория]|mode flag|exclude|confidence]' - список цен, которые пора обновить, или настройка, через сколько дней цены категории устаревают и как оценки их учитывают. Менять настройки могут только администраторы.",
+		"help.watch":      "'/c watch <название предмета> cost|margin|price <|> <значение> [here|dm|#канал]' - оповещение, когда значение пересекает порог, например '/c watch Стальной слиток margin>0 dm'. 'watch list' и 'watch remove <id>' управляют наблюдениями.",
+		"help.craftable":  "'/c craftable [list]' - что можно скрафтить из инвентаря или вставленного списка ('10 x Железная руда' в строке).",
+		"help.dm":         "Мне можно писать и в личные сообщения. Заданные там цены хранятся в вашем личном прайс-листе.",
+		"help.raceopt":    "Любая команда принимает '--race <раса>', чтобы один раз использовать другую расу, например '/c price Золотой слиток --race asmo'.",
+		"help.norace":     "Выберите расу одной из следующих команд:\n\t'/c race Elyos' - для элийцев\n\t'/c race Asmodian' - для асмодиан.\n\nРасу можно будет изменить позже. Добавьте 'channel' или 'me', чтобы выбрать её только для этого канала или для себя.",
+		"help.prefixnote": "Префикс команд на этом сервере: '%v'",
+		"help.invite":     "Чтобы добавить меня на свой сервер, используйте эту ссылку: %v",
+		"help.source":     "Мой исходный код здесь: %v",
+	},
+	"ko": {
+		"set.book":          "개인 가격표에 %[2]v (%[3]v)의 가격 (%[1]v)을 설정했습니다",
+		"set.done":          "%[2]v (%[3]v)의 가격 (%[1]v)을 설정했습니다",
+		"item.notfound":     "아이템 (%v)을 찾을 수 없습니다.",
+		"price.craft":       "종류: %v (레벨 %v), 아이템: %v (x%v), 가격: %v",
+		"price.base":        "종류: 기본 재료, 아이템: %v, 가격: %v",
+		"price.none":        "다음 표현식과 일치하는 아이템이 없습니다: \"%v\"",
+		"price.vendor":      "(NPC 상점)",
+		"price.improve":     "다음 가격을 추가하면 '<N/A>' 없이 더 정확한 견적을 받을 수 있습니다:",
+		"how.manual":        "종류: %v (레벨 %v), 아이템: %v (x%v), 제작 방법:\n%v",
+		"how.notfound":      "아이템을 찾을 수 없습니다: \"%v\"",
+		"how.buy":           "먼저 구매: ",
+		"how.buyline":       "%v x %v, 개당 %v, ",
+		"how.craft":         "그다음 제작: ",
+		"how.source":        "도안: %v",
+		"how.cost":          "비용: %v",
+		"how.kinah":         "%v 키나",
+		"how.ap":            "%v AP",
+		"how.tier":          "숙련도: %v",
+		"how.workorder":     "작업 의뢰",
+		"import.parse":      "파일을 읽을 수 없습니다: %v",
+		"import.empty":      "파일에 가격이 없습니다",
+		"import.emptyname":  "%v행: 아이템 이름이 없습니다",
+		"import.price":      "%v행: %v의 가격이 잘못되었습니다: \"%v\"",
+		"import.notfound":   "%v행: 아이템 (%v)을 찾을 수 없습니다",
+		"import.done":       "%v/%v개의 가격을 가져왔습니다.",
+		"import.skipped":    "건너뛴 행:",
+		"inv.removed":       "%v을 인벤토리에서 제거했습니다",
+		"inv.count":         "현재 %v x %v 보유",
+		"inv.empty":         "인벤토리가 비어 있습니다",
+		"inv.list":          "인벤토리:",
+		"race.first":        "먼저 종족을 선택하세요 (%v help 참고)",
+		"cmd.unknown":       "알 수 없는 명령어: \"%v\"",
+		"lang.set":          "언어가 %v(으)로 설정되었습니다",
+		"lang.unknown":      "\"%v\" 언어는 지원되지 않습니다. 지원 언어: %v",
+		"patch.none":        "기록된 레시피 변경 사항이 없습니다",
+		"uses.none":         "%v은(는) 어떤 레시피에도 사용되지 않습니다",
+		"uses.title":        "%v은(는) %v개의 레시피에 사용됩니다 ([깊이]):",
+		"uses.line":         "[%v] %v, %v (레벨 %v): %v, 제작당 %v",
+		"uses.more":         "...외 %v개",
+		"level.title":       "%v 숙련도 %v → %v:",
+		"level.step":        "%v-%v: %v x %v (레벨 %v), 개당 %v",
+		"level.gap":         "%v-%v: 숙련도를 올릴 레시피가 없습니다",
+		"level.total":       "합계: %v",
+		"level.range":       "레벨은 0에서 %v 사이이고 첫 번째가 두 번째보다 작아야 합니다",
+		"level.usage":       "사용법: level <제작> <시작> <끝>, 예: level 연금술 1 100",
+		"help.missing":      "주의: %v 레시피가 로드되지 않았습니다. 해당 제작의 가격과 제작법을 사용할 수 없습니다.",
+		"craft.Alchemy":     "연금술",
+		"craft.Armorsmith":  "갑옷 제작",
+		"craft.Cooking":     "요리",
+		"craft.Tailoring":   "재단",
+		"craft.Weaponsmith": "무기 제작",
+		"craft.Handicraft":  "세공",
+		"craft.Morph":       "변환",
+
+		"craftable.none":     "이 재료로는 몇 가지를 더 구매해도 제작할 수 있는 것이 없습니다",
+		"craftable.ready":    "지금 제작 가능:",
+		"craftable.line":     "%v x %v (%v, 레벨 %v)",
+		"craftable.near":     "한두 번의 구매로 제작 가능:",
+		"craftable.nearline": "%v (%v, 레벨 %v): %v 구매, %v",
+
+		"profit.none":  "수익성 있는 레시피가 없습니다. 먼저 제품 판매가와 재료 가격을 설정하세요",
+		"profit.title": "%[2]v에 %[1]v 키나를 가장 잘 쓰는 방법:",
+		"profit.line":  "%v x %v (레벨 %v): 지출 %v, 수익 %v",
+		"profit.total": "지출: %v, 수입: %v, 수익: %v",
+		"profit.buy":   "구매할 재료:",
+		"profit.usage": "사용법: profit <예산> <제작> [최대 레벨], 예: profit 5m 연금술 300",
+
+		"tree.craft": "%v, 레벨 %v, 제작 %v회",
+		"tree.usage": "사용법: tree [dot|mermaid|png] <아이템 이름>, 예: tree mermaid 강철 주괴",
+
+		"watch.usage":    "사용법: %[1]v watch <아이템 이름> cost|margin|price <|> <값> [here|dm|#채널], 예: %[1]v watch 강철 주괴 margin>0 dm",
+		"watch.added":    "감시 #%v 추가됨: %v %v %v %v",
+		"watch.removed":  "감시 #%v 삭제됨",
+		"watch.notfound": "감시를 찾을 수 없습니다: %v",
+		"watch.denied":   "작성자나 관리자만 감시 #%v를 삭제할 수 있습니다",
+		"watch.channel":  "알림은 이 서버의 채널로만 보낼 수 있습니다. <#%v>는 이 서버의 채널이 아닙니다",
+		"watch.none":     "아직 감시가 없습니다",
+		"watch.title":    "감시 목록:",
+		"watch.line":     "#%v: %v %v %v %v (%v), 알림 대상 %v",
+		"watch.alert":    "감시 #%v: %v %v 현재 %v (%v %v)",
+		"watch.cost":     "제작 비용",
+		"watch.margin":   "마진",
+		"watch.price":    "가격",
+		"watch.dm":       "개인 메시지",
+
+		"digest.profit":      "가장 수익성 높은 제작:",
+		"digest.profitline":  "%v (%v, 레벨 %v): 제작당 수익 %v, 비용 %v, 판매가 %v",
+		"digest.stale":       "%v일 이상 지난 가격:",
+		"digest.staleline":   "%v: %v, 설정일 %v",
+		"digest.nodate":      "날짜 기록 이전",
+		"digest.missing":     "가장 많은 견적을 막는 누락된 가격:",
+		"digest.missingline": "%v: 레시피 %v개",
+		"digest.none":        "이 서버에 요약이 없습니다",
+		"digest.title":       "요약:",
+		"digest.line":        "<#%[2]v>에서 %[1]v, 매일 %[3]v:00 UTC",
+		"digest.days":        "(%v일 이상)",
+		"digest.usage":       "사용법: %v digest [list|add <종류> [UTC 시] [일]|remove <종류>|now <종류>], 종류: %v",
+		"digest.admin":       "서버 관리자만 요약을 설정할 수 있습니다",
+		"digest.added":       "%v 요약이 매일 %v:00 UTC에 이 채널에 게시됩니다",
+		"digest.removed":     "%v 요약이 이 채널에서 삭제되었습니다",
+		"digest.notfound":    "이 채널에 %v 요약이 없습니다",
+
+		"stale.flag":       "오래된 가격 %v개",
+		"stale.confidence": "신뢰도 %v%%",
+		"stale.item":       "(오래됨, 설정일 %v)",
+		"stale.none":       "오래된 가격이 없습니다",
+		"stale.title":      "오래된 가격 (%v):",
+		"stale.config":     "가격은 %v일 후 오래된 것으로 처리됩니다. 카테고리별: %v. 모드: %v",
+		"stale.usage":      "사용법: %v stale [list|config|days <일> [카테고리]|mode %v]",
+		"stale.admin":      "서버 관리자만 오래된 가격을 설정할 수 있습니다",
+		"stale.days":       "%v의 가격은 %v일 후 오래된 것으로 처리됩니다",
+		"stale.mode":       "오래된 가격 모드: %v",
+		"stale.other":      "기타 아이템",
+		"stale.more":       "...외 오래된 가격 %v개",
+
+		"cmd.guildonly":     "이 명령어는 개인 메시지에서 사용할 수 없습니다",
+		"attach.load":       "첨부 파일을 불러올 수 없습니다: %v",
+		"import.attach":     "가격이 담긴 CSV 또는 JSON 파일을 메시지에 첨부하세요",
+		"set.format":        "잘못된 명령어 형식: 아이템 또는 가격을 찾을 수 없습니다",
+		"set.parse":         "잘못된 명령어 형식: 가격을 읽을 수 없습니다: %v",
+		"race.none":         "종족이 선택되지 않았습니다. '%v race <종족> [server|channel|me]'를 사용하세요",
+		"race.current":      "현재 종족은 %v입니다. 변경하려면 '%v race <종족> [server|channel|me]'를 사용하세요",
+		"race.wrong":        "잘못된 종족: %v",
+		"race.server":       "종족이 %v(으)로 설정되었습니다",
+		"race.serverreset":  "서버 종족이 초기화되었습니다",
+		"race.channel":      "이 채널의 종족이 %v(으)로 설정되었습니다",
+		"race.channelreset": "채널 종족이 초기화되어 서버 종족을 사용합니다",
+		"race.user":         "내 종족이 %v(으)로 설정되었습니다",
+		"race.userreset":    "내 종족이 초기화되어 채널 또는 서버 종족을 사용합니다",
+		"race.scope":        "알 수 없는 범위 \"%v\". server, channel 또는 me를 사용하세요",
+		"inv.cleared":       "인벤토리를 비웠습니다",
+		"inv.usage":         "잘못된 명령어 형식: '%v inv add|remove <아이템> <개수>'를 사용하세요",
+		"inv.unknown":       "알 수 없는 인벤토리 명령어: \"%v\"",
+		"perm.denied":       "이 서버에서 '%v'을(를) 사용할 권한이 없습니다",
+		"perm.readonly":     "(서버가 읽기 전용 모드입니다)",
+		"perm.admin":        "서버 관리자만 권한을 관리할 수 있습니다",
+		"perm.usage":        "잘못된 명령어 형식: '%v perm grant|revoke <권한> <역할>'을 사용하세요",
+		"perm.unknown":      "알 수 없는 권한 \"%v\". 사용 가능한 권한: %v",
+		"perm.roles":        "서버 역할을 불러올 수 없습니다: %v",
+		"perm.norole":       "역할 \"%v\"을(를) 찾을 수 없습니다",
+		"perm.granted":      "%v 역할이 이제 '%v'을(를) 사용할 수 있습니다",
+		"perm.revoked":      "%v 역할이 더 이상 '%v'을(를) 사용할 수 없습니다",
+		"perm.open":         "남은 역할이 없어 '%v'은(는) 모두에게 열려 있습니다",
+		"perm.command":      "알 수 없는 권한 명령어: \"%v\"",
+		"perm.mode":         "읽기 전용 모드: %v",
+		"perm.admins":       "관리자만",
+		"perm.everyone":     "모두",
+		"readonly.admin":    "서버 관리자만 읽기 전용 모드를 변경할 수 있습니다",
+		"readonly.on":       "읽기 전용 모드가 켜졌습니다. 관리자와 허용된 역할만 데이터를 변경할 수 있습니다",
+		"readonly.off":      "읽기 전용 모드가 꺼졌습니다",
+		"readonly.usage":    "읽기 전용 모드: %v. 변경하려면 '%v readonly on|off'를 사용하세요",
+		"mode.on":           "켜짐",
+		"mode.off":          "꺼짐",
+		"prefix.current":    "현재 접두사는 '%[1]v'입니다. 변경하려면 '%[1]v prefix <새 접두사>'를 사용하세요",
+		"prefix.admin":      "서버 관리자만 접두사를 변경할 수 있습니다",
+		"prefix.invalid":    "접두사는 10자 이하의 한 단어여야 합니다",
+		"prefix.set":        "접두사가 '%[1]v'(으)로 설정되었습니다. '%[1]v help'를 입력해 보세요",
+		"alias.none":        "설정된 별칭이 없습니다",
+		"alias.list":        "별칭: %v",
+		"alias.admin":       "서버 관리자만 별칭을 변경할 수 있습니다",
+		"alias.command":     "\"%v\"은(는) 이미 명령어입니다",
+		"alias.added":       "'%[1]v %[2]v'이(가) 이제 '%[1]v %[3]v'처럼 동작합니다",
+		"alias.unknown":     "알 수 없는 별칭: \"%v\"",
+		"alias.removed":     "별칭 \"%v\"이(가) 삭제되었습니다",
+		"alias.nopreset":    "\"%v\" 언어의 별칭이 없습니다. 사용 가능한 언어: %v",
+		"alias.preset":      "별칭이 추가되었습니다: %v",
+		"alias.cleared":     "모든 별칭이 삭제되었습니다",
+		"alias.usage":       "잘못된 명령어 형식: '%v alias [list|add <별칭> <명령어>|remove <별칭>|lang <%v>|clear]'를 사용하세요",
+		"lang.admin":        "서버 관리자만 언어를 변경할 수 있습니다",
+		"announce.admin":    "서버 관리자만 공지를 설정할 수 있습니다",
+		"announce.here":     "패치 변경 사항이 이 채널에 공지됩니다",
+		"announce.off":      "패치 공지가 꺼졌습니다",
+		"announce.usage":    "패치 공지 채널을 설정하려면 '%v announce here|off'를 사용하세요",
+		"announce.channel":  "패치 변경 사항이 <#%v>에 공지됩니다.",
+		"patch.admin":       "서버 관리자만 레시피 변경 사항을 공지 채널에 게시할 수 있습니다",
+		"patch.nochan":      "공지 채널이 설정되지 않았습니다. 먼저 '%v announce here'를 사용하세요",
+		"patch.posted":      "레시피 변경 사항을 <#%v>에 게시했습니다",
+
+		"help.title":      "지원되는 명령어:",
+		"help.help":       "'/c help' - 이 도움말을 표시합니다",
+		"help.race":       "'/c race <종족> [server|channel|me]' - 서버(기본), 이 채널 또는 나의 종족을 설정합니다. 'reset'으로 초기화합니다.",
+		"help.set":        "'/c set <아이템 이름> <가격>' - 아이템 가격을 설정합니다. 정확한 이름이 필요합니다.",
+		"help.price":      "'/c price <아이템 이름>' - 제작 비용 추정치를 표시합니다. 이름에 정규식을 사용할 수 있습니다.",
+		"help.how":        "'/c how <아이템 이름>' - 제작 트리 이미지와 함께 제작 방법을 표시합니다. 정확한 이름이 필요합니다.",
+		"help.uses":       "'/c uses <아이템 이름>' - 아이템을 직접 또는 다른 제작을 통해 사용하는 레시피를 표시합니다.",
+		"help.level":      "'/c level <제작> <시작> <끝>' - 제작 숙련도를 가장 싸게 올리는 방법을 계획합니다. 예: '/c level 연금술 1 100'.",
+		"help.profit":     "'/c profit <예산> <제작> [최대 레벨]' - 가장 큰 이익을 내는 제작 및 판매 레시피를 고릅니다. 예: '/c profit 5m 연금술 300'. 인벤토리를 먼저 사용합니다.",
+		"help.tree":       "'/c tree [dot|mermaid|png] <아이템 이름>' - 제작 트리를 Graphviz 또는 Mermaid 그래프나 이미지로 첨부합니다. 기본값은 Mermaid입니다.",
+		"help.import":     "'/c import' - 첨부한 CSV(아이템,가격) 또는 JSON 파일로 가격을 일괄 설정합니다.",
+		"help.export":     "'/c export' - 현재 가격을 CSV 파일로 내려받습니다.",
+		"help.perm":       "'/c perm [list|grant|revoke <권한> <역할>]' - set, race, import를 사용하고 감시를 변경할 수 있는 역할을 관리합니다. 관리자 전용.",
+		"help.readonly":   "'/c readonly on|off' - 관리자와 허용된 역할만 변경할 수 있게 합니다. 관리자 전용.",
+		"help.prefix":     "'/c prefix <접두사>' - 명령어 접두사를 변경합니다. 관리자 전용.",
+		"help.patch":      "'/c patch [announce]' - 마지막 게임 패치의 레시피 변경 사항을 표시합니다. 관리자는 'announce'로 공지 채널에 게시할 수 있습니다.",
+		"help.announce":   "'/c announce here|off' - 데이터베이스가 갱신되면 레시피 변경 사항을 이 채널에 게시합니다. 관리자 전용.",
+		"help.digest":     "'/c digest [list|add <종류> [UTC 시] [일]|remove <종류>|now <종류>]' - 이 채널에 일일 보고서를 게시합니다: profit(가장 수익성 높은 제작), stale(지정한 일수보다 오래된 가격) 또는 missing(가장 많은 추정을 막는 가격). 관리자 전용.",
+		"help.lang":       "'/c lang <언어>' - 응답 언어를 변경합니다 (%v). 관리자 전용.",
+		"help.alias":      "'/c alias [list|add <별칭> <명령어>|remove <별칭>|lang <언어>|clear]' - 명령어 별칭을 관리합니다. 예: '/c alias lang ko'. 관리자 전용.",
+		"help.inv":        "'/c inv [add|remove <아이템 이름> <개수>|clear]' - 개인 인벤토리를 관리합니다.",
+		"help.stale":      "'/c stale [list|config|days <일> [카테고리]|mode flag|exclude|confidence]' - 갱신이 필요한 가격을 나열하거나, 카테고리별로 며칠 후 가격이 오래된 것으로 처리되는지와 추정에 어떻게 쓰이는지 설정합니다. 설정 변경은 관리자 전용입니다.",
+		"help.watch":      "'/c watch <아이템 이름> cost|margin|price <|> <값> [here|dm|#채널]' - 값이 기준을 넘으면 알립니다. 예: '/c watch 강철 주괴 margin>0 dm'. 'watch list'와 'watch remove <id>'로 감시를 관리합니다.",
+		"help.craftable":  "'/c craftable [list]' - 인벤토리나 붙여넣은 목록(한 줄에 '10 x 철광석')으로 제작할 수 있는 것을 표시합니다.",
+		"help.dm":         "개인 메시지로도 대화할 수 있습니다. 그곳에서 설정한 가격은 개인 가격표에 저장됩니다.",
+		"help.raceopt":    "모든 명령어는 '--race <종족>'으로 한 번만 다른 종족을 사용할 수 있습니다. 예: '/c price 금 주괴 --race asmo'.",
+		"help.norace":     "다음 명령어 중 하나로 종족을 선택하세요:\n\t'/c race Elyos' - 천족\n\t'/c race Asmodian' - 마족.\n\n종족은 나중에 변경할 수 있습니다. 이 채널이나 나에게만 설정하려면 'channel' 또는 'me'를 추가하세요.",
+		"help.prefixnote": "이 서버의 명령어 접두사는 '%v'입니다",
+		"help.invite":     "저를 서버에 추가하려면 이 링크를 사용하세요: %v",
+		"help.source":     "제 소스 코드는 여기 있습니다: %v",
+	},
+}
+
+// tr renders the catalog message in the language.
+func tr(lang string, key string, args ...interface{}) string {
+	format, ok := catalog[lang][key]
+	if !ok {
+		format, ok = catalog[database.DefaultLocale][key]
+	}
+	if !ok {
+		return key
+	}
+
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+func isLanguage(lang string) bool {
+	_, ok := catalog[lang]
+	return ok
+}
+
+func languages() string {
+	rv := []string{}
+	for l := range catalog {
+		rv = append(rv, l)
+	}
+	sort.Strings(rv)
+
+	return strings.Join(rv, ", ")
+}
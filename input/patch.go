@@ -0,0 +1,131 @@
+package input
+
+import (
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/google/martian/v3/log"
+	"github.com/mebaranov/aioncraft/utility"
+)
+
+const patchLimit = 25
+
+// Patch renders recipe changes of the last database refresh. Nothing is returned if the changes are not newer than cmd.Since.
+func (p *Processor) Patch(cmd Command) string {
+	diff := p.db.LastDiff
+	if diff == nil {
+		if !cmd.Since.IsZero() {
+			return ""
+		}
+		return tr(cmd.Lang, "patch.none")
+	}
+	if !cmd.Since.IsZero() && !diff.Created.After(cmd.Since) {
+		return ""
+	}
+
+	return diff.Markdown(patchLimit)
+}
+
+func (d *Discord) announce(s *discordgo.Session, m *discordgo.MessageCreate, g *Guild, args string) {
+	if m.GuildID == "" {
+		msg := tr(g.Language, "cmd.guildonly")
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+		return
+	}
+	if !d.isAdmin(s, m) {
+		msg := tr(g.Language, "announce.admin")
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+		return
+	}
+
+	var msg string
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "here":
+		d.update(m, func(g *Guild) {
+			g.Announce = m.ChannelID
+			g.Announced = time.Now()
+			d.SaveNeeded = true
+		})
+		msg = tr(g.Language, "announce.here")
+	case "off":
+		d.update(m, func(g *Guild) {
+			g.Announce = ""
+			d.SaveNeeded = true
+		})
+		msg = tr(g.Language, "announce.off")
+	default:
+		msg = tr(g.Language, "announce.usage", g.prefix())
+		if g.Announce != "" {
+			msg = tr(g.Language, "announce.channel", g.Announce) + " " + msg
+		}
+	}
+
+	utility.SendMonitored(s, &m.ChannelID, &msg)
+}
+
+// patch replies with the last recipe changes. Administrators can post them to the announcements channel with 'patch announce'.
+func (d *Discord) patch(s *discordgo.Session, m *discordgo.MessageCreate, g *Guild, args string) {
+	announce := strings.ToLower(strings.TrimSpace(args)) == "announce"
+	if announce {
+		if m.GuildID == "" || !d.isAdmin(s, m) {
+			msg := tr(g.Language, "patch.admin")
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+		if g.Announce == "" {
+			msg := tr(g.Language, "patch.nochan", g.prefix())
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+	}
+
+	g.cmdc <- Command{
+		Action: Patch,
+		Lang:   g.Language,
+		Out:    g.outc,
+	}
+	msg := <-g.outc
+
+	if !announce {
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+		return
+	}
+
+	channel := g.Announce
+	d.update(m, func(g *Guild) {
+		g.Announced = time.Now()
+		d.SaveNeeded = true
+	})
+	utility.SendMonitored(s, &channel, &msg)
+	if channel != m.ChannelID {
+		posted := tr(g.Language, "patch.posted", channel)
+		utility.SendMonitored(s, &m.ChannelID, &posted)
+	}
+}
+
+// announcePatch posts recipe changes which were not announced to the guild yet.
+func (d *Discord) announcePatch(s *discordgo.Session, g *Guild) {
+	d.mu.Lock()
+	channel, cmd := g.Announce, Command{Action: Patch, Lang: g.Language, Since: g.Announced}
+	d.mu.Unlock()
+	if channel == "" {
+		return
+	}
+
+	// Guilds are announced concurrently on start, so each one waits on its own channel
+	out := make(chan string, 1)
+	cmd.Out = out
+	g.cmdc <- cmd
+	msg := <-out
+	if msg == "" {
+		return
+	}
+
+	log.Infof("Announcing patch changes to channel %v", channel)
+	d.mu.Lock()
+	g.Announced = time.Now()
+	d.SaveNeeded = true
+	d.mu.Unlock()
+	utility.SendMonitored(s, &channel, &msg)
+}
@@ -0,0 +1,262 @@
+package input
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/mebaranov/aioncraft/utility"
+)
+
+const (
+	PermSet    = "set"
+	PermRace   = "race"
+	PermImport = "import"
+	PermWatch  = "watch"
+)
+
+var permissions = []string{PermSet, PermRace, PermImport, PermWatch}
+
+// commandPerms maps commands changing shared data to the permission they require. Watches are listed by everyone,
+// so the watch command checks its permission only when watches are changed.
+var commandPerms = map[string]string{
+	"set":    PermSet,
+	"race":   PermRace,
+	"import": PermImport,
+}
+
+const adminPerms = discordgo.PermissionAdministrator | discordgo.PermissionManageServer
+
+// allowed reports if a member having the roles may use the permission.
+// Permission without any granted roles is open for everyone unless the guild is read-only.
+func (g *Guild) allowed(perm string, roles []string) bool {
+	granted := g.Roles[perm]
+	if len(granted) == 0 {
+		return !g.ReadOnly
+	}
+
+	for _, r := range roles {
+		for _, gr := range granted {
+			if r == gr {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (d *Discord) isAdmin(s *discordgo.Session, m *discordgo.MessageCreate) bool {
+	if guild, err := s.State.Guild(m.GuildID); err == nil && guild.OwnerID == m.Author.ID {
+		return true
+	}
+
+	perms, err := s.State.UserChannelPermissions(m.Author.ID, m.ChannelID)
+	if err != nil {
+		perms, err = s.UserChannelPermissions(m.Author.ID, m.ChannelID)
+		if err != nil {
+			return false
+		}
+	}
+
+	return perms&adminPerms != 0
+}
+
+// checkPermission reports if the author may use the permission and tells them if they may not.
+func (d *Discord) checkPermission(s *discordgo.Session, m *discordgo.MessageCreate, g *Guild, perm string) bool {
+	roles := []string{}
+	if m.Member != nil {
+		roles = m.Member.Roles
+	}
+	if g.allowed(perm, roles) || d.isAdmin(s, m) {
+		return true
+	}
+
+	msg := tr(g.Language, "perm.denied", perm)
+	if g.ReadOnly {
+		msg += " " + tr(g.Language, "perm.readonly")
+	}
+	utility.SendMonitored(s, &m.ChannelID, &msg)
+	return false
+}
+
+func (d *Discord) perm(s *discordgo.Session, m *discordgo.MessageCreate, g *Guild, args string) {
+	if !d.isAdmin(s, m) {
+		msg := tr(g.Language, "perm.admin")
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+		return
+	}
+
+	params := strings.SplitN(strings.TrimSpace(args), " ", 3)
+	switch strings.ToLower(params[0]) {
+	case "", "list":
+		msg := d.permList(s, m.GuildID, g)
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+	case "grant", "revoke":
+		if len(params) != 3 {
+			msg := tr(g.Language, "perm.usage", g.prefix())
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+
+		perm := strings.ToLower(params[1])
+		if !isPermission(perm) {
+			msg := tr(g.Language, "perm.unknown", params[1], strings.Join(permissions, ", "))
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+
+		role, err := findRole(s, m.GuildID, params[2])
+		if err != nil {
+			msg := tr(g.Language, "perm.roles", err)
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+		if role == nil {
+			msg := tr(g.Language, "perm.norole", params[2])
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+
+		var msg string
+		d.update(m, func(g *Guild) {
+			if strings.ToLower(params[0]) == "grant" {
+				g.grant(perm, role.ID)
+				msg = tr(g.Language, "perm.granted", role.Name, perm)
+			} else {
+				g.revoke(perm, role.ID)
+				msg = tr(g.Language, "perm.revoked", role.Name, perm)
+				if len(g.Roles[perm]) == 0 && !g.ReadOnly {
+					msg += ". " + tr(g.Language, "perm.open", perm)
+				}
+			}
+			d.SaveNeeded = true
+		})
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+	default:
+		msg := tr(g.Language, "perm.command", params[0])
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+	}
+}
+
+func (d *Discord) readOnly(s *discordgo.Session, m *discordgo.MessageCreate, g *Guild, args string) {
+	if !d.isAdmin(s, m) {
+		msg := tr(g.Language, "readonly.admin")
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+		return
+	}
+
+	var msg string
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "on", "true", "1":
+		d.update(m, func(g *Guild) {
+			g.ReadOnly = true
+			d.SaveNeeded = true
+		})
+		msg = tr(g.Language, "readonly.on")
+	case "off", "false", "0":
+		d.update(m, func(g *Guild) {
+			g.ReadOnly = false
+			d.SaveNeeded = true
+		})
+		msg = tr(g.Language, "readonly.off")
+	default:
+		msg = tr(g.Language, "readonly.usage", g.onOff(g.ReadOnly), g.prefix())
+	}
+	utility.SendMonitored(s, &m.ChannelID, &msg)
+}
+
+func (d *Discord) permList(s *discordgo.Session, guildID string, g *Guild) string {
+	names := map[string]string{}
+	if roles, err := s.GuildRoles(guildID); err == nil {
+		for _, r := range roles {
+			names[r.ID] = r.Name
+		}
+	}
+
+	rv := tr(g.Language, "perm.mode", g.onOff(g.ReadOnly)) + "\n"
+	for _, perm := range permissions {
+		granted := []string{}
+		for _, id := range g.Roles[perm] {
+			if name, ok := names[id]; ok {
+				granted = append(granted, name)
+			} else {
+				granted = append(granted, id)
+			}
+		}
+		sort.Strings(granted)
+
+		switch {
+		case len(granted) > 0:
+			rv += fmt.Sprintf("\t%v: %v\n", perm, strings.Join(granted, ", "))
+		case g.ReadOnly:
+			rv += fmt.Sprintf("\t%v: %v\n", perm, tr(g.Language, "perm.admins"))
+		default:
+			rv += fmt.Sprintf("\t%v: %v\n", perm, tr(g.Language, "perm.everyone"))
+		}
+	}
+
+	return rv
+}
+
+func (g *Guild) grant(perm string, roleID string) {
+	if g.Roles == nil {
+		g.Roles = map[string][]string{}
+	}
+	for _, id := range g.Roles[perm] {
+		if id == roleID {
+			return
+		}
+	}
+	g.Roles[perm] = append(g.Roles[perm], roleID)
+}
+
+func (g *Guild) revoke(perm string, roleID string) {
+	roles := []string{}
+	for _, id := range g.Roles[perm] {
+		if id != roleID {
+			roles = append(roles, id)
+		}
+	}
+	if len(roles) == 0 {
+		delete(g.Roles, perm)
+		return
+	}
+	g.Roles[perm] = roles
+}
+
+func isPermission(perm string) bool {
+	for _, p := range permissions {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// findRole looks the role up by mention (<@&id>), ID or case insensitive name. Nil is returned if there is no such role.
+func findRole(s *discordgo.Session, guildID string, in string) (*discordgo.Role, error) {
+	in = strings.TrimSpace(in)
+	id := strings.TrimSuffix(strings.TrimPrefix(in, "<@&"), ">")
+
+	roles, err := s.GuildRoles(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range roles {
+		if r.ID == id || strings.ToLower(r.Name) == strings.ToLower(in) {
+			return r, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (g *Guild) onOff(b bool) string {
+	if b {
+		return tr(g.Language, "mode.on")
+	}
+	return tr(g.Language, "mode.off")
+}
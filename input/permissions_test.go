@@ -0,0 +1,89 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// testSession has guild g1 with channel c1 and members of every kind, and guild g2 with channel c2.
+func testSession(t *testing.T) *discordgo.Session {
+	s := &discordgo.Session{State: discordgo.NewState()}
+	guilds := []*discordgo.Guild{{
+		ID:      "g1",
+		OwnerID: "owner",
+		Roles: []*discordgo.Role{
+			{ID: "g1", Name: "@everyone"},
+			{ID: "admin", Name: "Admin", Permissions: discordgo.PermissionAdministrator},
+			{ID: "manager", Name: "Manager", Permissions: discordgo.PermissionManageServer},
+			{ID: "crafter", Name: "Crafter", Permissions: discordgo.PermissionSendMessages},
+		},
+		Channels: []*discordgo.Channel{{ID: "c1", GuildID: "g1"}},
+		Members: []*discordgo.Member{
+			{GuildID: "g1", User: &discordgo.User{ID: "owner"}},
+			{GuildID: "g1", User: &discordgo.User{ID: "admin"}, Roles: []string{"admin"}},
+			{GuildID: "g1", User: &discordgo.User{ID: "manager"}, Roles: []string{"manager"}},
+			{GuildID: "g1", User: &discordgo.User{ID: "crafter"}, Roles: []string{"crafter"}},
+		},
+	}, {
+		ID:       "g2",
+		OwnerID:  "other",
+		Roles:    []*discordgo.Role{{ID: "g2", Name: "@everyone"}},
+		Channels: []*discordgo.Channel{{ID: "c2", GuildID: "g2"}},
+	}}
+	for _, g := range guilds {
+		if err := s.State.GuildAdd(g); err != nil {
+			t.Fatalf("Could not add guild: %v", err)
+		}
+	}
+	return s
+}
+
+func testMessage(guildID string, channelID string, userID string) *discordgo.MessageCreate {
+	return &discordgo.MessageCreate{Message: &discordgo.Message{GuildID: guildID, ChannelID: channelID, Author: &discordgo.User{ID: userID}}}
+}
+
+func TestIsAdmin(t *testing.T) {
+	tests := []struct {
+		user string
+		want bool
+	}{
+		{"owner", true},
+		{"admin", true},
+		{"manager", true},
+		{"crafter", false},
+	}
+
+	s, d := testSession(t), &Discord{}
+	for _, tt := range tests {
+		t.Run(tt.user, func(t *testing.T) {
+			if got := d.isAdmin(s, testMessage("g1", "c1", tt.user)); got != tt.want {
+				t.Errorf("isAdmin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	tests := []struct {
+		name     string
+		readOnly bool
+		granted  []string
+		roles    []string
+		want     bool
+	}{
+		{"open", false, nil, nil, true},
+		{"read-only", true, nil, []string{"crafter"}, false},
+		{"granted", true, []string{"crafter"}, []string{"crafter"}, true},
+		{"not granted", false, []string{"crafter"}, []string{"other"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Guild{ReadOnly: tt.readOnly, Roles: map[string][]string{PermWatch: tt.granted}}
+			if got := g.allowed(PermWatch, tt.roles); got != tt.want {
+				t.Errorf("allowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
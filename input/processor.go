@@ -5,28 +5,21 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/mebaranov/aioncraft/database"
 	"github.com/mebaranov/aioncraft/utility"
 )
 
 type Processor struct {
-	db *database.Database
+	db        *database.Database
+	listeners []PriceListener
 }
 
 func NewProcessor(db *database.Database) *Processor {
 	return &Processor{db: db}
 }
 
-var CraftTypeToName = map[database.CraftType]string{
-	database.Alchemy:    "Alchemy",
-	database.Armor:      "Armorsmith",
-	database.Cooking:    "Cooking",
-	database.Tailor:     "Tailoring",
-	database.Weapon:     "Weaponsmith",
-	database.Handicraft: "Handicraft",
-}
-
 func (p *Processor) Work(inputs []InputController) {
 	length := len(inputs)
 	cmdChan := make(chan Command, 15)
@@ -34,6 +27,9 @@ func (p *Processor) Work(inputs []InputController) {
 
 	for i := 0; i < length; i++ {
 		outChans[i] = make(chan string, 15)
+		if l, ok := inputs[i].(PriceListener); ok {
+			p.listeners = append(p.listeners, l)
+		}
 		go inputs[i].Start(cmdChan, outChans[i])
 	}
 
@@ -49,24 +45,58 @@ func (p *Processor) Work(inputs []InputController) {
 			cmd.Out <- p.Price(cmd)
 		case Help:
 			cmd.Out <- p.Help(cmd)
+		case Import:
+			cmd.Out <- p.Import(cmd)
+		case Export:
+			cmd.Out <- p.Export(cmd)
+		case InventoryAdd:
+			cmd.Out <- p.InventoryAdd(cmd)
+		case InventoryList:
+			cmd.Out <- p.InventoryList(cmd)
+		case Patch:
+			cmd.Out <- p.Patch(cmd)
+		case MissingCrafts:
+			cmd.Out <- p.MissingCrafts(cmd)
+		case Uses:
+			cmd.Out <- p.Uses(cmd)
+		case Level:
+			cmd.Out <- p.Level(cmd)
+		case Craftable:
+			cmd.Out <- p.Craftable(cmd)
+		case Profit:
+			// The optimizer is slow, so it works on a copy and other commands are not held up
+			sp, spCmd := p.profitSnapshot(cmd)
+			go func() { spCmd.Out <- sp.Profit(spCmd) }()
+		case Graph:
+			cmd.Out <- p.Graph(cmd)
+		case WatchCheck:
+			cmd.Out <- p.CheckWatch(cmd)
+		case DigestReport:
+			cmd.Out <- p.Digest(cmd)
+		case StaleList:
+			cmd.Out <- p.Stale(cmd)
 		}
 	}
 }
 
 func (p *Processor) Set(cmd Command) string {
-	items := p.db.Items[cmd.Race]
-	name := strings.ToLower(strings.TrimSpace(cmd.Item))
-	for _, it := range items {
-		if strings.ToLower(it.Name) == name {
-			it.Price.NAReasons = []string{}
-			it.Price.Value = cmd.Price
-			p.db.SaveNeeded = true
-
-			return fmt.Sprintf("Price (%v) successfully set for item %v (%v)", it.Price.Value, it.Name, it.ID)
+	if it := p.findItem(cmd.Race, cmd.Item); it != nil {
+		if cmd.Book != nil {
+			cmd.Book[it.ID] = cmd.Price
+			p.notify(cmd.Race, it.ID)
+			return tr(cmd.Lang, "set.book", cmd.Price, it.LocalName(cmd.Lang), it.ID)
 		}
+
+		it.Price.NAReasons = []string{}
+		it.Price.Value = cmd.Price
+		it.Updated = time.Now()
+		p.db.SaveNeeded = true
+		p.notify(cmd.Race, it.ID)
+
+		return tr(cmd.Lang, "set.done", it.Price.Value, it.LocalName(cmd.Lang), it.ID)
 	}
 
-	return fmt.Sprintf("Item (%v) was not found.", cmd.Item)
+	return tr(cmd.Lang, "item.notfound", cmd.Item)
 }
 
 type helpStruct struct {
@@ -80,33 +110,43 @@ func (p *Processor) Price(cmd Command) string {
 	regEx := regexp.MustCompile(strings.ToLower(cmd.Item))
 	naReasons := map[string]bool{}
 	rvs := []*helpStruct{}
+	pr := newPricing(cmd)
 
 	for _, item := range items {
-		if regEx.MatchString(strings.ToLower(item.Name)) {
+		if matchAny(item, func(name string) bool { return regEx.MatchString(strings.ToLower(name)) }) {
 			found := false
-			for ct, ctName := range CraftTypeToName {
+			for _, ct := range database.Crafts {
 				rec := p.db.RecipeByItem(cmd.Race, ct, item.ID)
 				if rec == nil {
 					continue
 				}
-				price := p.priceByRecipe(cmd.Race, ct, rec.ID, true)
+				price, note := p.staleEstimate(cmd.Race, ct, rec.ID, pr, cmd.Lang)
 
-				tmpstr := fmt.Sprintf("Type: %v (Level %v), Item: %v (x%v), Price: %v", ctName, rec.Level, item.Name, rec.Count, price.Value)
+				tmpstr := tr(cmd.Lang, "price.craft", tr(cmd.Lang, "craft."+ct.String()), rec.Level, item.LocalName(cmd.Lang), rec.Count, price.Value)
 				if len(price.NAReasons) > 0 {
 					tmpstr += " + <N/A>."
 					for _, na := range price.NAReasons {
 						naReasons[na] = true
 					}
 				}
+				if note != "" {
+					tmpstr += " (" + note + ")"
+				}
 				tmpstr += "\n"
 				rvs = append(rvs, &helpStruct{tmpstr, rec.Level + int(ct)*1000})
 				found = true
 			}
 
 			if !found {
-				str := fmt.Sprintf("Type: Base item, Item: %v, Price: %v", item.Name, item.Price.Value)
-				if len(item.Price.NAReasons) != 0 {
+				price := p.itemPrice(cmd.Race, item.ID, pr)
+				str := tr(cmd.Lang, "price.base", item.LocalName(cmd.Lang), price.Value)
+				if len(price.NAReasons) != 0 {
 					str += " (<N/A>)."
+				} else if p.vendorPriced(cmd.Race, item.ID, pr) {
+					str += " " + tr(cmd.Lang, "price.vendor")
+				}
+				if p.stalePrice(cmd.Race, item.ID, pr) && pr.stale.Mode != StaleExclude {
+					str += " " + tr(cmd.Lang, "stale.item", updatedDate(item, cmd.Lang))
 				}
 				str += "\n"
 				rvs = append(rvs, &helpStruct{str, -1})
@@ -115,7 +155,7 @@ func (p *Processor) Price(cmd Command) string {
 	}
 
 	if len(rvs) == 0 {
-		rv = fmt.Sprintf("No items found following expression: \"%v\"", cmd.Item)
+		rv = tr(cmd.Lang, "price.none", cmd.Item)
 	} else {
 		sort.SliceStable(rvs, func(i, j int) bool {
 			return rvs[i].layer < rvs[j].layer
@@ -124,7 +164,7 @@ func (p *Processor) Price(cmd Command) string {
 			rv += s.str
 		}
 		if len(naReasons) != 0 {
-			rv += "\n\nYou can improve estimation quality and get rid of '<N/A>'s by adding the following prices:\n"
+			rv += "\n\n" + tr(cmd.Lang, "price.improve") + "\n"
 			for i := range naReasons {
 				rv += i + ","
 			}
@@ -138,122 +178,239 @@ func (p *Processor) Help(cmd Command) string {
 	items := p.db.Items[cmd.Race]
 	rv := ""
 
-	for _, item := range items {
-		if strings.ToLower(item.Name) == strings.ToLower(cmd.Item) {
-			for ct, name := range CraftTypeToName {
+	ids := make([]string, 0, len(items))
+	for id := range items {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		item := items[id]
+		if matchAny(item, func(name string) bool { return strings.ToLower(name) == strings.ToLower(cmd.Item) }) {
+			for _, ct := range database.Crafts {
+				name := ct.String()
 				rec := p.db.RecipeByItem(cmd.Race, ct, item.ID)
 				if rec == nil {
 					continue
 				}
-				help := p.gatherIngridients(cmd.Race, ct, rec.ID)
-				rv += fmt.Sprintf("Type: %v (Level %v), Item: %v (x%v), Manual:\n%v", name, rec.Level, item.Name, rec.Count, help)
+				help := p.gatherIngridients(cmd.Race, ct, rec.ID, newPricing(cmd), cmd.Lang)
+				rv += tr(cmd.Lang, "how.manual", tr(cmd.Lang, "craft."+name), rec.Level, item.LocalName(cmd.Lang), rec.Count, help)
+				if info := recipeInfo(rec.Info, cmd.Lang); info != "" {
+					rv += info + "\n"
+				}
 				rv += "==========================\n"
 			}
 		}
 	}
 
 	if rv == "" {
-		rv = fmt.Sprintf("Item not found: \"%v\"", cmd.Item)
+		rv = tr(cmd.Lang, "how.notfound", cmd.Item)
 	}
 	return rv
 }
 
-type itemAndCount struct {
-	name  string
-	count int
-	layer int
-	price *utility.TheInt
+// MissingCrafts warns about crafts without recipes for the race. Nothing is returned if all crafts have recipes.
+func (p *Processor) MissingCrafts(cmd Command) string {
+	names := []string{}
+	for _, ct := range p.db.EmptyCrafts(cmd.Race) {
+		names = append(names, tr(cmd.Lang, "craft."+ct.String()))
+	}
+	if len(names) == 0 {
+		return ""
+	}
+
+	return tr(cmd.Lang, "help.missing", strings.Join(names, ", "))
 }
 
-type queueItem struct {
-	id  string
-	mul int
+// recipeInfo describes where the design is learned and what it costs. Nothing is returned for unknown metadata.
+func recipeInfo(info *database.RecipeInfo, lang string) string {
+	if info == nil {
+		return ""
+	}
+
+	parts := []string{}
+	if info.Source != "" {
+		parts = append(parts, tr(lang, "how.source", info.Source))
+	}
+	costs := []string{}
+	if info.Kinah > 0 {
+		costs = append(costs, tr(lang, "how.kinah", info.Kinah))
+	}
+	if info.AP > 0 {
+		costs = append(costs, tr(lang, "how.ap", info.AP))
+	}
+	if len(costs) > 0 {
+		parts = append(parts, tr(lang, "how.cost", strings.Join(costs, " + ")))
+	}
+	if info.Tier != "" {
+		parts = append(parts, tr(lang, "how.tier", info.Tier))
+	}
+	if info.WorkOrder {
+		parts = append(parts, tr(lang, "how.workorder"))
+	}
+
+	return strings.Join(parts, "; ")
 }
 
-func (p *Processor) gatherIngridients(race database.Race, ct database.CraftType, inRecId string) string {
+// craftStep is an item crafted for a manual. Need is the amount the parents consume, ingredients from subs are
+// crafted too and the rest are bought.
+type craftStep struct {
+	ct      database.CraftType
+	rec     *database.Recipe
+	name    string
+	need    int
+	crafts  int
+	subs    map[string]bool
+	parents int
+}
 
+// gatherIngridients writes the manual of a single craft of the recipe. Quantities of items needed by several crafts
+// are summed before their own ingredients are counted, and crafts are listed in topological order, ingredients first.
+// Items with the same position are ordered by name, so the manual is always the same.
+func (p *Processor) gatherIngridients(race database.Race, ct database.CraftType, inRecId string, pr *pricing, lang string) string {
 	rec := p.db.Recipes[race][ct][inRecId]
-	item := p.db.Items[race][rec.ItemID]
-	queue := []*queueItem{{inRecId, 1}}
-	baseItems := map[string]*itemAndCount{}
-	crafts := map[string]*itemAndCount{
-		item.ID: {item.Name, rec.Count, 0, nil},
-	}
-
-	layer := 0
-	for len(queue) > 0 {
-		theRec := queue[0]
-		queue = queue[1:]
-		rec = p.db.Recipes[race][ct][theRec.id]
-
-		for id, count := range rec.Items {
-			subRec := p.db.RecipeByItem(race, ct, id)
-			if subRec == nil {
-				if c, ok := baseItems[id]; ok {
-					c.count += count * theRec.mul
-				} else {
-					theItem := p.db.Items[race][id]
-					baseItems[id] = &itemAndCount{theItem.Name, count * theRec.mul, -1, theItem.Price}
-				}
-			} else {
-				layer += 1
-				if c, ok := crafts[id]; ok {
-					c.layer = layer
-					c.count += count * theRec.mul
-				} else {
-					crafts[id] = &itemAndCount{
-						name:  p.db.Items[race][id].Name,
-						count: count * theRec.mul,
-						layer: layer,
-					}
-					queue = append(queue, &queueItem{subRec.ID, count * theRec.mul})
+	root := &craftStep{ct: ct, rec: rec, name: p.itemName(race, rec.ItemID, lang), need: rec.Count, subs: map[string]bool{}}
+	steps := map[string]*craftStep{rec.ItemID: root}
+	bought := map[string]bool{}
+
+	// Every item is crafted or bought the way it is decided at its first visit. Items crafted up the path are bought,
+	// so the graph has no cycles.
+	path := map[string]bool{}
+	var discover func(step *craftStep)
+	discover = func(step *craftStep) {
+		path[step.rec.ID] = true
+		defer delete(path, step.rec.ID)
+
+		for _, id := range sortedIngredients(step.rec) {
+			if sub, ok := steps[id]; ok {
+				if !path[sub.rec.ID] {
+					step.subs[id] = true
+					sub.parents += 1
 				}
+				continue
+			}
+			if bought[id] {
+				continue
+			}
+
+			subCt, subRec := p.subRecipe(race, step.ct, id, pr, path)
+			if subRec == nil || subRec.Count <= 0 {
+				bought[id] = true
+				continue
 			}
+
+			name := p.itemName(race, id, lang)
+			if subCt == database.Morph && ct != database.Morph {
+				name += " [" + tr(lang, "craft."+subCt.String()) + "]"
+			}
+			sub := &craftStep{ct: subCt, rec: subRec, name: name, subs: map[string]bool{}, parents: 1}
+			steps[id] = sub
+			step.subs[id] = true
+			discover(sub)
 		}
 	}
+	discover(root)
+
+	// Kahn's algorithm from the product down: an item is counted once all crafts consuming it are.
+	baseItems := map[string]int{}
+	order := []*craftStep{}
+	ready := []*craftStep{root}
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool {
+			return stepLess(ready[i], ready[j])
+		})
+		step := ready[0]
+		ready = ready[1:]
+		order = append(order, step)
+
+		step.crafts = (step.need + step.rec.Count - 1) / step.rec.Count
+		for _, id := range sortedIngredients(step.rec) {
+			count := step.rec.Items[id] * step.crafts
+			if !step.subs[id] {
+				baseItems[id] += count
+				continue
+			}
 
-	layers := []*itemAndCount{}
-	for _, it := range crafts {
-		layers = append(layers, it)
+			sub := steps[id]
+			sub.need += count
+			if sub.parents -= 1; sub.parents == 0 {
+				ready = append(ready, sub)
+			}
+		}
 	}
 
-	sort.SliceStable(layers, func(i int, j int) bool {
-		return layers[i].layer > layers[j].layer
+	ids := make([]string, 0, len(baseItems))
+	for id := range baseItems {
+		ids = append(ids, id)
+	}
+	names := map[string]string{}
+	for _, id := range ids {
+		names[id] = p.itemName(race, id, lang)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if names[ids[i]] != names[ids[j]] {
+			return names[ids[i]] < names[ids[j]]
+		}
+		return ids[i] < ids[j]
 	})
 
-	rv := "First you buy: "
-	for _, it := range baseItems {
+	rv := tr(lang, "how.buy")
+	for _, id := range ids {
 		prc := "N/A"
-		if len(it.price.NAReasons) == 0 {
-			prc = fmt.Sprint(it.price.Value)
+		if price := p.itemPrice(race, id, pr); len(price.NAReasons) == 0 {
+			prc = fmt.Sprint(price.Value)
 		}
-		rv += fmt.Sprintf("\n\t%v x %v, for %v each, ", it.count, it.name, prc)
+		rv += "\n\t" + tr(lang, "how.buyline", baseItems[id], names[id], prc)
 	}
-	rv += "\nThen you craft: "
+	rv += "\n" + tr(lang, "how.craft")
 
-	for _, it := range layers {
-		rv += fmt.Sprintf("--> %v (%v) ", it.name, it.count)
+	for i := len(order) - 1; i >= 0; i-- {
+		rv += fmt.Sprintf("--> %v (%v) ", order[i].name, order[i].need)
 	}
 	rv += "\n"
 
 	return rv
 }
 
-func (p *Processor) priceByRecipe(race database.Race, ct database.CraftType, id string, ignoreCount bool) *utility.TheInt {
-	similarRecs := p.db.Recipes[race][ct]
-	rec := similarRecs[id]
-	mainRec := rec
+// stepLess orders crafts which are ready at the same time. The reversed order is printed, so the names are compared
+// backwards to list them alphabetically.
+func stepLess(a *craftStep, b *craftStep) bool {
+	if a.name != b.name {
+		return a.name > b.name
+	}
+	return a.rec.ID > b.rec.ID
+}
+
+func sortedIngredients(rec *database.Recipe) []string {
+	ids := make([]string, 0, len(rec.Items))
+	for id := range rec.Items {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (p *Processor) priceByRecipe(race database.Race, ct database.CraftType, id string, ignoreCount bool, pr *pricing) *utility.TheInt {
+	return p.recipePrice(race, ct, id, ignoreCount, pr, map[string]bool{})
+}
+
+// recipePrice sums prices of the recipe ingredients. Path holds recipes being priced up the chain and is used to stop
+// morph chains which lead back to an item already being made.
+func (p *Processor) recipePrice(race database.Race, ct database.CraftType, id string, ignoreCount bool, pr *pricing, path map[string]bool) *utility.TheInt {
+	mainRec := p.db.Recipes[race][ct][id]
 	rv := &utility.TheInt{Value: 0}
 
-	for item, count := range rec.Items {
+	path[id] = true
+	defer delete(path, id)
+
+	for item, count := range mainRec.Items {
 		var recPrice *utility.TheInt
 
-		rec := p.db.RecipeByItem(race, ct, item)
+		subCt, rec := p.subRecipe(race, ct, item, pr, path)
 		if rec == nil {
-			it := p.db.Items[race][item]
-			recPrice = it.Price
+			recPrice = p.itemPrice(race, item, pr)
 		} else {
-			recPrice = p.priceByRecipe(race, ct, rec.ID, false)
+			recPrice = p.recipePrice(race, subCt, rec.ID, false, pr, path)
 		}
 
 		curPrice := recPrice.Mul(count)
@@ -265,3 +422,91 @@ func (p *Processor) priceByRecipe(race database.Race, ct database.CraftType, id
 
 	return rv
 }
+
+// subRecipe finds the recipe making an ingredient of a ct recipe. Recipes of the same craft are used first. Otherwise
+// the ingredient may be morphed, which is done only when the item has no price or morphing is cheaper than buying it.
+// Recipes from path are skipped. Returns nil recipe if the item should be bought.
+func (p *Processor) subRecipe(race database.Race, ct database.CraftType, itemID string, pr *pricing, path map[string]bool) (database.CraftType, *database.Recipe) {
+	if ct != database.Morph {
+		if rec := p.db.RecipeByItem(race, ct, itemID); rec != nil && !path[rec.ID] {
+			return ct, rec
+		}
+	}
+
+	rec := p.db.RecipeByItem(race, database.Morph, itemID)
+	if rec == nil || path[rec.ID] {
+		return ct, nil
+	}
+
+	if price := p.itemPrice(race, itemID, pr); len(price.NAReasons) == 0 {
+		morph := p.recipePrice(race, database.Morph, rec.ID, false, pr, path)
+		if len(morph.NAReasons) != 0 || morph.Value >= price.Value {
+			return ct, nil
+		}
+	}
+
+	return database.Morph, rec
+}
+
+// pricing tells an estimate how to price base items. Book holds personal prices which override the shared ones and
+// stale is the stale price settings. Seen collects stale items the estimate used, fresh prices them at zero.
+type pricing struct {
+	book  map[string]int
+	stale *Staleness
+	seen  map[string]bool
+	fresh bool
+}
+
+func newPricing(cmd Command) *pricing {
+	return &pricing{book: cmd.Book, stale: cmd.Stale}
+}
+
+// personal returns the price of the item from the book.
+func (pr *pricing) personal(id string) (int, bool) {
+	if pr == nil {
+		return 0, false
+	}
+	price, ok := pr.book[id]
+	return price, ok
+}
+
+// itemPrice returns the price of a base item. Prices from the book override the shared ones.
+// NPC vendor price is used for items nobody set the price for. Stale prices are unknown if they are excluded.
+func (p *Processor) itemPrice(race database.Race, id string, pr *pricing) *utility.TheInt {
+	if price, ok := pr.personal(id); ok {
+		return &utility.TheInt{Value: price}
+	}
+
+	if p.vendorPriced(race, id, pr) {
+		return &utility.TheInt{Value: p.db.Items[race][id].VendorPrice()}
+	}
+	if p.stalePrice(race, id, pr) {
+		switch {
+		case pr.stale.Mode == StaleExclude:
+			return utility.NewInt(0, p.db.Items[race][id].Name)
+		case pr.fresh:
+			return &utility.TheInt{}
+		}
+	}
+	return p.db.Items[race][id].Price
+}
+
+// vendorPriced reports if itemPrice falls back to the NPC vendor price for the item.
+func (p *Processor) vendorPriced(race database.Race, id string, pr *pricing) bool {
+	if _, ok := pr.personal(id); ok {
+		return false
+	}
+
+	item := p.db.Items[race][id]
+	return item.VendorPrice() > 0 && (item.Price == nil || len(item.Price.NAReasons) != 0)
+}
+
+// matchAny reports if the item name in any locale satisfies the check.
+func matchAny(item *database.Item, check func(string) bool) bool {
+	for _, name := range item.AllNames() {
+		if check(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/mebaranov/aioncraft/database"
+	"github.com/mebaranov/aioncraft/utility"
+)
+
+// morphDatabase has weapon W made of the ingot I, which can also be morphed from two ores O.
+func morphDatabase(ingot *utility.TheInt, ore int) *database.Database {
+	db := database.New()
+	db.Items[database.Elyos]["W"] = &database.Item{ID: "W", Name: "Weapon", Price: utility.NewInt(0, "W")}
+	db.Items[database.Elyos]["I"] = &database.Item{ID: "I", Name: "Ingot", Price: ingot}
+	db.Items[database.Elyos]["O"] = &database.Item{ID: "O", Name: "Ore", Price: &utility.TheInt{Value: ore}}
+	db.Recipes[database.Elyos][database.Weapon]["rW"] = &database.Recipe{ID: "rW", ItemID: "W", Count: 1, Items: map[string]int{"I": 1}}
+	db.Recipes[database.Elyos][database.Morph]["rI"] = &database.Recipe{ID: "rI", ItemID: "I", Count: 1, Items: map[string]int{"O": 2}}
+	return db
+}
+
+func TestSubRecipeMorph(t *testing.T) {
+	tests := []struct {
+		name  string
+		ingot *utility.TheInt
+		ore   int
+		craft database.CraftType
+		want  string
+	}{
+		{"cheaper", &utility.TheInt{Value: 100}, 30, database.Morph, "rI"},
+		{"dearer", &utility.TheInt{Value: 100}, 60, database.Weapon, ""},
+		{"equal", &utility.TheInt{Value: 100}, 50, database.Weapon, ""},
+		{"no price", utility.NewInt(0, "I"), 60, database.Morph, "rI"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProcessor(morphDatabase(tt.ingot, tt.ore))
+			ct, rec := p.subRecipe(database.Elyos, database.Weapon, "I", nil, map[string]bool{})
+			got := ""
+			if rec != nil {
+				got = rec.ID
+			}
+			if ct != tt.craft || got != tt.want {
+				t.Errorf("subRecipe() = %v, %q, want %v, %q", ct, got, tt.craft, tt.want)
+			}
+		})
+	}
+}
+
+// TestSubRecipeMorphPath checks that a morph recipe already being priced is not used again.
+func TestSubRecipeMorphPath(t *testing.T) {
+	p := NewProcessor(morphDatabase(utility.NewInt(0, "I"), 30))
+	if _, rec := p.subRecipe(database.Elyos, database.Weapon, "I", nil, map[string]bool{"rI": true}); rec != nil {
+		t.Errorf("subRecipe() = %v, want nil", rec.ID)
+	}
+}
+
+func TestPriceMorph(t *testing.T) {
+	p := NewProcessor(morphDatabase(&utility.TheInt{Value: 100}, 30))
+	if got := p.priceByRecipe(database.Elyos, database.Weapon, "rW", true, nil); got.Value != 60 || len(got.NAReasons) != 0 {
+		t.Errorf("priceByRecipe() = %v, want 60", got)
+	}
+}
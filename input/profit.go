@@ -0,0 +1,293 @@
+package input
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mebaranov/aioncraft/database"
+)
+
+const (
+	// profitMaxCrafts limits crafts of a single recipe, as the broker would not buy unlimited amounts.
+	profitMaxCrafts = 20
+	// profitBuckets is the amount of steps the budget is split into for the optimizer.
+	profitBuckets = 1000
+)
+
+// profitPlan keeps the state of the optimizer. Inv holds owned items and intermediates left over from previous
+// crafts, so recipes sharing materials take them from there before buying new ones.
+type profitPlan struct {
+	inv  map[string]int
+	buy  map[string]int
+	cost int
+}
+
+// profitOption is crafting the recipe crafts times, costs are rounded up to budget steps.
+type profitOption struct {
+	crafts int
+	cost   int
+	steps  int
+	profit int
+}
+
+type profitLine struct {
+	rec    *database.Recipe
+	crafts int
+	cost   int
+	income int
+}
+
+// Profit picks recipes of cmd.Craft up to level cmd.To and their amounts to get the most profit for cmd.Price kinah.
+// Products are sold for their set prices. The cost of every amount of crafts of a recipe is computed once with the
+// owned inventory, then a bounded knapsack over the budget split into profitBuckets steps picks the amounts. Recipes
+// are costed separately, so the plan is built again in the end to share materials and leftovers between them.
+func (p *Processor) Profit(cmd Command) string {
+	pr := newPricing(cmd)
+	recs := []*database.Recipe{}
+	for _, rec := range p.db.Recipes[cmd.Race][cmd.Craft] {
+		if rec.Removed || (cmd.To > 0 && rec.Level > cmd.To) {
+			continue
+		}
+		if sell := p.itemPrice(cmd.Race, rec.ItemID, pr); len(sell.NAReasons) != 0 || sell.Value <= 0 {
+			continue
+		}
+		recs = append(recs, rec)
+	}
+	sort.Slice(recs, func(i, j int) bool {
+		return recs[i].ID < recs[j].ID
+	})
+
+	step := (cmd.Price + profitBuckets - 1) / profitBuckets
+	if step < 1 {
+		step = 1
+	}
+	steps := cmd.Price / step
+
+	// best[w] is the most profit for w budget steps. Picks[i][w] is the option of recs[i] giving it, counted from 1.
+	best := make([]int, steps+1)
+	picks := make([][]int8, len(recs))
+	options := make([][]*profitOption, len(recs))
+	for i, rec := range recs {
+		options[i] = p.profitOptions(cmd, rec, pr, step, steps)
+		picks[i] = make([]int8, steps+1)
+		next := append([]int(nil), best...)
+		for w := range next {
+			for j, o := range options[i] {
+				if o.steps <= w && best[w-o.steps]+o.profit > next[w] {
+					next[w], picks[i][w] = best[w-o.steps]+o.profit, int8(j+1)
+				}
+			}
+		}
+		best = next
+	}
+
+	chosen := map[string]int{}
+	for i, w := len(recs)-1, steps; i >= 0; i-- {
+		if j := picks[i][w]; j > 0 {
+			o := options[i][j-1]
+			chosen[recs[i].ID] = o.crafts
+			w -= o.steps
+		}
+	}
+
+	pl := &profitPlan{inv: copyCounts(cmd.Inventory), buy: map[string]int{}}
+	lines := []*profitLine{}
+	income := 0
+	for _, rec := range recs {
+		sell := p.itemPrice(cmd.Race, rec.ItemID, pr).Value * rec.Count
+		for crafts := chosen[rec.ID]; crafts > 0; crafts-- {
+			// Recipes sharing owned materials may cost more together than apart, then fewer crafts are made
+			trial := &profitPlan{inv: copyCounts(pl.inv), buy: copyCounts(pl.buy), cost: pl.cost}
+			if !p.craftInto(cmd.Race, cmd.Craft, rec, crafts, trial, pr, map[string]bool{rec.ID: true}) || trial.cost > cmd.Price || sell*crafts <= trial.cost-pl.cost {
+				continue
+			}
+			lines = append(lines, &profitLine{rec: rec, crafts: crafts, cost: trial.cost - pl.cost, income: sell * crafts})
+			income += sell * crafts
+			pl = trial
+			break
+		}
+	}
+
+	if len(lines) == 0 {
+		return tr(cmd.Lang, "profit.none")
+	}
+
+	sort.Slice(lines, func(i, j int) bool {
+		a, b := lines[i].income-lines[i].cost, lines[j].income-lines[j].cost
+		if a != b {
+			return a > b
+		}
+		return lines[i].rec.ID < lines[j].rec.ID
+	})
+
+	rv := tr(cmd.Lang, "profit.title", cmd.Price, tr(cmd.Lang, "craft."+cmd.Craft.String()))
+	for _, l := range lines {
+		rv += "\n\t" + tr(cmd.Lang, "profit.line", l.crafts, p.productName(cmd.Race, l.rec, cmd.Lang), l.rec.Level, l.cost, l.income-l.cost)
+	}
+	rv += "\n" + tr(cmd.Lang, "profit.total", pl.cost, income, income-pl.cost)
+
+	if len(pl.buy) > 0 {
+		buy := []string{}
+		for id, count := range pl.buy {
+			name := id
+			if it, ok := p.db.Items[cmd.Race][id]; ok && it.Name != "" {
+				name = it.LocalName(cmd.Lang)
+			}
+			buy = append(buy, strconv.Itoa(count)+" x "+name)
+		}
+		sort.Strings(buy)
+		rv += "\n" + tr(cmd.Lang, "profit.buy") + "\n\t" + strings.Join(buy, "\n\t")
+	}
+
+	return rv
+}
+
+// profitOptions lists profitable amounts of crafts of the recipe fitting into steps budget steps of step kinah.
+// Crafting several at once may be cheaper per craft, as intermediates made in batches are left over.
+func (p *Processor) profitOptions(cmd Command, rec *database.Recipe, pr *pricing, step int, steps int) []*profitOption {
+	sell := p.itemPrice(cmd.Race, rec.ItemID, pr).Value * rec.Count
+	rv := []*profitOption{}
+	for crafts := 1; crafts <= profitMaxCrafts; crafts++ {
+		pl := &profitPlan{inv: copyCounts(cmd.Inventory), buy: map[string]int{}}
+		if !p.craftInto(cmd.Race, cmd.Craft, rec, crafts, pl, pr, map[string]bool{rec.ID: true}) {
+			break
+		}
+		o := &profitOption{crafts: crafts, cost: pl.cost, steps: (pl.cost + step - 1) / step, profit: sell*crafts - pl.cost}
+		if o.steps > steps {
+			break
+		}
+		if o.profit > 0 {
+			rv = append(rv, o)
+		}
+	}
+	return rv
+}
+
+// profitSnapshot copies what Profit reads and may be changed by other commands meanwhile: item prices of the race,
+// the price book and the inventory. Recipes are not changed while the bot runs, so they are shared.
+func (p *Processor) profitSnapshot(cmd Command) (*Processor, Command) {
+	db := database.New()
+	db.Recipes = p.db.Recipes
+	for id, it := range p.db.Items[cmd.Race] {
+		item := *it
+		if it.Price != nil {
+			price := *it.Price
+			price.NAReasons = append([]string(nil), it.Price.NAReasons...)
+			item.Price = &price
+		}
+		db.Items[cmd.Race][id] = &item
+	}
+	if cmd.Book != nil {
+		cmd.Book = copyCounts(cmd.Book)
+	}
+	cmd.Inventory = copyCounts(cmd.Inventory)
+
+	return NewProcessor(db), cmd
+}
+
+// craftInto adds crafting the recipe times times to the plan. Ingredients are taken from the plan inventory first,
+// then crafted the same way priceByRecipe would do or bought, whichever is cheaper. Surplus of intermediate crafts
+// goes to the inventory. Returns false if a price of a material is not known.
+func (p *Processor) craftInto(race database.Race, ct database.CraftType, rec *database.Recipe, times int, pl *profitPlan, pr *pricing, path map[string]bool) bool {
+	ids := make([]string, 0, len(rec.Items))
+	for id := range rec.Items {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		need := rec.Items[id] * times
+		take := pl.inv[id]
+		if take > need {
+			take = need
+		}
+		pl.inv[id] -= take
+		need -= take
+		if need == 0 {
+			continue
+		}
+
+		price := p.itemPrice(race, id, pr)
+		known := price != nil && len(price.NAReasons) == 0
+
+		// Intermediates are crafted on a copy of the plan, which is kept unless buying them is cheaper.
+		subCt, sub := p.subRecipe(race, ct, id, pr, path)
+		if sub != nil && sub.Count > 0 {
+			n := (need + sub.Count - 1) / sub.Count
+			trial := &profitPlan{inv: copyCounts(pl.inv), buy: copyCounts(pl.buy), cost: pl.cost}
+			path[sub.ID] = true
+			ok := p.craftInto(race, subCt, sub, n, trial, pr, path)
+			delete(path, sub.ID)
+			if ok && (!known || trial.cost-pl.cost <= need*price.Value) {
+				*pl = *trial
+				pl.inv[id] += n*sub.Count - need
+				continue
+			}
+		}
+
+		if !known {
+			return false
+		}
+		pl.buy[id] += need
+		pl.cost += need * price.Value
+	}
+
+	return true
+}
+
+func copyCounts(in map[string]int) map[string]int {
+	rv := make(map[string]int, len(in))
+	for id, count := range in {
+		rv[id] = count
+	}
+	return rv
+}
+
+// parseKinah reads amounts like 5000000, 5kk, 500k or 5m.
+func parseKinah(s string) (int, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	mul := 1
+	for _, suffix := range []struct {
+		text string
+		mul  int
+	}{{"kk", 1000000}, {"m", 1000000}, {"k", 1000}} {
+		if strings.HasSuffix(s, suffix.text) {
+			s, mul = strings.TrimSuffix(s, suffix.text), suffix.mul
+			break
+		}
+	}
+
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil || value <= 0 {
+		return 0, false
+	}
+	return int(value * float64(mul)), true
+}
+
+// parseProfitArgs splits '<budget> <craft> [level cap]'. The craft name may have spaces.
+func parseProfitArgs(lang string, args string) (int, database.CraftType, int, bool) {
+	fields := strings.Fields(args)
+	if len(fields) < 2 {
+		return 0, 0, 0, false
+	}
+
+	budget, ok := parseKinah(fields[0])
+	if !ok {
+		return 0, 0, 0, false
+	}
+	fields = fields[1:]
+
+	maxLevel := 0
+	if len(fields) > 1 {
+		if l, err := strconv.Atoi(fields[len(fields)-1]); err == nil {
+			maxLevel, fields = l, fields[:len(fields)-1]
+		}
+	}
+
+	ct, ok := parseCraft(lang, strings.Join(fields, " "))
+	if !ok {
+		return 0, 0, 0, false
+	}
+	return budget, ct, maxLevel, true
+}
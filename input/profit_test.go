@@ -0,0 +1,52 @@
+package input
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mebaranov/aioncraft/database"
+	"github.com/mebaranov/aioncraft/utility"
+)
+
+// TestProfitSurplus checks that a craft taking the intermediate left over from a previous craft is priced again.
+// Products A and B are made of X, which is crafted in pairs from four ores O.
+func TestProfitSurplus(t *testing.T) {
+	db := database.New()
+	db.Items[database.Elyos]["A"] = &database.Item{ID: "A", Name: "Alpha", Price: &utility.TheInt{Value: 100}}
+	db.Items[database.Elyos]["B"] = &database.Item{ID: "B", Name: "Beta", Price: &utility.TheInt{Value: 90}}
+	db.Items[database.Elyos]["X"] = &database.Item{ID: "X", Name: "Ex", Price: utility.NewInt(0, "X")}
+	db.Items[database.Elyos]["O"] = &database.Item{ID: "O", Name: "Ore", Price: &utility.TheInt{Value: 10}}
+	db.Recipes[database.Elyos][database.Alchemy]["rA"] = &database.Recipe{ID: "rA", ItemID: "A", Count: 1, Items: map[string]int{"X": 1}}
+	db.Recipes[database.Elyos][database.Alchemy]["rB"] = &database.Recipe{ID: "rB", ItemID: "B", Count: 1, Items: map[string]int{"X": 1}}
+	db.Recipes[database.Elyos][database.Alchemy]["rX"] = &database.Recipe{ID: "rX", ItemID: "X", Count: 2, Items: map[string]int{"O": 4}}
+
+	p := NewProcessor(db)
+	got := p.Profit(Command{Race: database.Elyos, Craft: database.Alchemy, Price: 40, Lang: database.DefaultLocale})
+	for _, want := range []string{"2 x Alpha", "Spent: 40, income: 200, profit: 160", "4 x Ore"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Profit() = %q, want %q in it", got, want)
+		}
+	}
+}
+
+// TestProfitBudget checks that amounts are picked for the most profit rather than by the profit to cost ratio. One
+// craft of P has the best ratio, but two crafts of Q fit the budget and earn more.
+func TestProfitBudget(t *testing.T) {
+	db := database.New()
+	db.Items[database.Elyos]["P"] = &database.Item{ID: "P", Name: "Pi", Price: &utility.TheInt{Value: 121}}
+	db.Items[database.Elyos]["Q"] = &database.Item{ID: "Q", Name: "Qu", Price: &utility.TheInt{Value: 95}}
+	db.Items[database.Elyos]["MP"] = &database.Item{ID: "MP", Name: "Pi Ore", Price: &utility.TheInt{Value: 60}}
+	db.Items[database.Elyos]["MQ"] = &database.Item{ID: "MQ", Name: "Qu Ore", Price: &utility.TheInt{Value: 50}}
+	db.Recipes[database.Elyos][database.Cooking]["rP"] = &database.Recipe{ID: "rP", ItemID: "P", Count: 1, Items: map[string]int{"MP": 1}}
+	db.Recipes[database.Elyos][database.Cooking]["rQ"] = &database.Recipe{ID: "rQ", ItemID: "Q", Count: 1, Items: map[string]int{"MQ": 1}}
+
+	got := NewProcessor(db).Profit(Command{Race: database.Elyos, Craft: database.Cooking, Price: 100, Lang: database.DefaultLocale})
+	for _, want := range []string{"2 x Qu", "Spent: 100, income: 190, profit: 90", "2 x Qu Ore"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Profit() = %q, want %q in it", got, want)
+		}
+	}
+	if strings.Contains(got, "Pi") {
+		t.Errorf("Profit() = %q, want no Pi crafts", got)
+	}
+}
@@ -0,0 +1,112 @@
+package input
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/mebaranov/aioncraft/database"
+	"github.com/mebaranov/aioncraft/utility"
+)
+
+var raceArgRegex = regexp.MustCompile(`(?i)(^|\s)--race[ =](\S+)`)
+
+// extractRace cuts '--race <race>' out of command arguments.
+func extractRace(args string) (string, string) {
+	match := raceArgRegex.FindStringSubmatchIndex(args)
+	if match == nil {
+		return args, ""
+	}
+
+	race := args[match[4]:match[5]]
+	return strings.TrimSpace(args[:match[0]] + args[match[1]:]), race
+}
+
+// race resolves the race for a message. User setting overrides channel one, which overrides the guild one.
+func (g *Guild) race(channelID string, userID string) (database.Race, bool) {
+	if r, ok := g.UserRaces[userID]; ok {
+		return r, true
+	}
+	if r, ok := g.ChannelRaces[channelID]; ok {
+		return r, true
+	}
+
+	return g.Race, g.IsRaceSelected
+}
+
+func (d *Discord) race(s *discordgo.Session, m *discordgo.MessageCreate, g *Guild, args string) {
+	params := strings.Fields(args)
+	if len(params) == 0 || len(params) > 2 {
+		r, ok := g.race(m.ChannelID, m.Author.ID)
+		msg := tr(g.Language, "race.none", g.prefix())
+		if ok {
+			msg = tr(g.Language, "race.current", r.String(), g.prefix())
+		}
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+		return
+	}
+
+	scope := "server"
+	if len(params) == 2 {
+		scope = strings.ToLower(params[1])
+	}
+
+	if scope != "me" && scope != "user" && !d.checkPermission(s, m, g, PermRace) {
+		return
+	}
+
+	reset := strings.ToLower(params[0]) == "reset"
+	r, ok := database.ParseRace(params[0])
+	if !ok && !reset {
+		msg := tr(g.Language, "race.wrong", params[0])
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+		return
+	}
+
+	switch scope {
+	case "server", "guild", "channel", "me", "user":
+	default:
+		msg := tr(g.Language, "race.scope", params[1])
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+		return
+	}
+
+	var msg string
+	d.update(m, func(g *Guild) {
+		switch scope {
+		case "server", "guild":
+			if reset {
+				g.IsRaceSelected = false
+				msg = tr(g.Language, "race.serverreset")
+				break
+			}
+			g.Race = r
+			g.IsRaceSelected = true
+			msg = tr(g.Language, "race.server", r.String())
+		case "channel":
+			if g.ChannelRaces == nil {
+				g.ChannelRaces = map[string]database.Race{}
+			}
+			if reset {
+				delete(g.ChannelRaces, m.ChannelID)
+				msg = tr(g.Language, "race.channelreset")
+				break
+			}
+			g.ChannelRaces[m.ChannelID] = r
+			msg = tr(g.Language, "race.channel", r.String())
+		case "me", "user":
+			if g.UserRaces == nil {
+				g.UserRaces = map[string]database.Race{}
+			}
+			if reset {
+				delete(g.UserRaces, m.Author.ID)
+				msg = tr(g.Language, "race.userreset")
+				break
+			}
+			g.UserRaces[m.Author.ID] = r
+			msg = tr(g.Language, "race.user", r.String())
+		}
+		d.SaveNeeded = true
+	})
+	utility.SendMonitored(s, &m.ChannelID, &msg)
+}
@@ -0,0 +1,193 @@
+package input
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mebaranov/aioncraft/database"
+	"github.com/mebaranov/aioncraft/utility"
+)
+
+const (
+	// nearMissItems is the most distinct ingredients a near miss may lack.
+	nearMissItems  = 2
+	craftableLimit = 25
+	nearMissLimit  = 15
+)
+
+var (
+	countFirstRegex = regexp.MustCompile(`^(\d+)\s*[xX*]?\s+(.+)$`)
+	countLastRegex  = regexp.MustCompile(`^(.+?)\s*[xX*:,]?\s*(\d+)$`)
+)
+
+type craftable struct {
+	ct    database.CraftType
+	rec   *database.Recipe
+	times int
+	// missing ingredients of near misses and their price for a single craft.
+	missing map[string]int
+	cost    *utility.TheInt
+}
+
+// Craftable lists recipes which can be crafted from the inventory and the ones lacking one or two ingredients.
+// The inventory is taken from cmd.Data if a list is pasted, otherwise from cmd.Inventory.
+func (p *Processor) Craftable(cmd Command) string {
+	inv, rv := cmd.Inventory, ""
+	if len(cmd.Data) > 0 {
+		var errs []string
+		inv, errs = p.parseInventory(cmd.Race, cmd.Lang, string(cmd.Data))
+		if len(errs) > 0 {
+			rv += tr(cmd.Lang, "import.skipped") + "\n\t" + strings.Join(errs, "\n\t") + "\n\n"
+		}
+	}
+	if len(inv) == 0 {
+		return rv + tr(cmd.Lang, "inv.empty")
+	}
+
+	ready, near := p.solve(cmd.Race, inv, newPricing(cmd))
+	if len(ready) == 0 && len(near) == 0 {
+		return rv + tr(cmd.Lang, "craftable.none")
+	}
+
+	if len(ready) > 0 {
+		rv += tr(cmd.Lang, "craftable.ready")
+		for i, c := range ready {
+			if i >= craftableLimit {
+				rv += "\n\t" + tr(cmd.Lang, "uses.more", len(ready)-i)
+				break
+			}
+			rv += "\n\t" + tr(cmd.Lang, "craftable.line", c.times, p.productName(cmd.Race, c.rec, cmd.Lang), tr(cmd.Lang, "craft."+c.ct.String()), c.rec.Level)
+		}
+		rv += "\n"
+	}
+
+	if len(near) > 0 {
+		rv += tr(cmd.Lang, "craftable.near")
+		for i, c := range near {
+			if i >= nearMissLimit {
+				rv += "\n\t" + tr(cmd.Lang, "uses.more", len(near)-i)
+				break
+			}
+
+			buy := []string{}
+			for id, count := range c.missing {
+				name := id
+				if it, ok := p.db.Items[cmd.Race][id]; ok && it.Name != "" {
+					name = it.LocalName(cmd.Lang)
+				}
+				buy = append(buy, strconv.Itoa(count)+" x "+name)
+			}
+			sort.Strings(buy)
+
+			cost := strconv.Itoa(c.cost.Value)
+			if len(c.cost.NAReasons) > 0 {
+				cost += " + <N/A>"
+			}
+			rv += "\n\t" + tr(cmd.Lang, "craftable.nearline", p.productName(cmd.Race, c.rec, cmd.Lang), tr(cmd.Lang, "craft."+c.ct.String()), c.rec.Level, strings.Join(buy, ", "), cost)
+		}
+	}
+
+	return rv
+}
+
+// solve finds recipes with all ingredients in the inventory and recipes lacking up to nearMissItems of them.
+// Only direct ingredients are counted. Near misses are sorted by the price of missing ingredients.
+func (p *Processor) solve(race database.Race, inv map[string]int, pr *pricing) ([]*craftable, []*craftable) {
+	ready, near := []*craftable{}, []*craftable{}
+
+	for _, ct := range database.Crafts {
+		for _, rec := range p.db.Recipes[race][ct] {
+			if rec.Removed || len(rec.Items) == 0 {
+				continue
+			}
+
+			times, owned := -1, false
+			missing := map[string]int{}
+			for id, count := range rec.Items {
+				have := inv[id]
+				if have > 0 {
+					owned = true
+				}
+				if have < count {
+					missing[id] = count - have
+				}
+				if t := have / count; times < 0 || t < times {
+					times = t
+				}
+			}
+
+			switch {
+			case len(missing) == 0:
+				ready = append(ready, &craftable{ct: ct, rec: rec, times: times})
+			case owned && len(missing) <= nearMissItems:
+				cost := &utility.TheInt{}
+				for id, count := range missing {
+					cost = cost.Plus(p.itemPrice(race, id, pr).Mul(count))
+				}
+				near = append(near, &craftable{ct: ct, rec: rec, missing: missing, cost: cost})
+			}
+		}
+	}
+
+	sort.Slice(ready, func(i, j int) bool {
+		if ready[i].rec.Level != ready[j].rec.Level {
+			return ready[i].rec.Level > ready[j].rec.Level
+		}
+		return ready[i].rec.ID < ready[j].rec.ID
+	})
+	sort.Slice(near, func(i, j int) bool {
+		if a, b := near[i].cost, near[j].cost; cheaper(a, b) != cheaper(b, a) {
+			return cheaper(a, b)
+		}
+		return near[i].rec.ID < near[j].rec.ID
+	})
+
+	return ready, near
+}
+
+// parseInventory reads lines like '10 x Iron Ore', 'Iron Ore 10' or 'Iron Ore: 10'. Lines may also be split with ';'.
+func (p *Processor) parseInventory(race database.Race, lang string, text string) (map[string]int, []string) {
+	rv := map[string]int{}
+	errs := []string{}
+
+	rows := strings.FieldsFunc(text, func(r rune) bool { return r == '\n' || r == ';' })
+	for i, row := range rows {
+		row = strings.TrimSpace(row)
+		if row == "" {
+			continue
+		}
+
+		// Names may end with digits, so the whole row is tried as a name first.
+		it, count := p.findItem(race, row), 1
+		name := row
+		if it == nil {
+			if m := countFirstRegex.FindStringSubmatch(row); m != nil {
+				name, count = m[2], atoi(m[1])
+			} else if m := countLastRegex.FindStringSubmatch(row); m != nil {
+				name, count = m[1], atoi(m[2])
+			}
+			it = p.findItem(race, name)
+		}
+		if it == nil {
+			errs = append(errs, tr(lang, "import.notfound", i+1, strings.TrimSpace(name)))
+			continue
+		}
+		rv[it.ID] += count
+	}
+
+	return rv, errs
+}
+
+func (p *Processor) productName(race database.Race, rec *database.Recipe, lang string) string {
+	if it, ok := p.db.Items[race][rec.ItemID]; ok && it.Name != "" {
+		return it.LocalName(lang)
+	}
+	return rec.Name
+}
+
+func atoi(s string) int {
+	rv, _ := strconv.Atoi(s)
+	return rv
+}
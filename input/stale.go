@@ -0,0 +1,257 @@
+package input
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/mebaranov/aioncraft/database"
+	"github.com/mebaranov/aioncraft/utility"
+)
+
+// Ways of using stale prices in estimates.
+const (
+	// StaleFlag only marks estimates using stale prices.
+	StaleFlag = "flag"
+	// StaleExclude treats stale prices as unknown.
+	StaleExclude = "exclude"
+	// StaleConfidence flags estimates like StaleFlag and also reports the share of the estimate coming from fresh
+	// prices. The estimate itself still uses stale prices at full value.
+	StaleConfidence = "confidence"
+)
+
+var staleModes = []string{StaleFlag, StaleExclude, StaleConfidence}
+
+// staleLegacyWeight is the name StaleConfidence was saved under before.
+const staleLegacyWeight = "weight"
+
+// staleLimit is the amount of stale prices listed at once.
+const staleLimit = 40
+
+// Staleness tells when shared prices need updating. Days maps lower case item categories to the age in days,
+// the empty category is used for the rest. The age of prices set before dates were tracked is unknown, so they
+// are stale until set again. Personal and vendor prices are never stale.
+type Staleness struct {
+	Days map[string]int
+	Mode string
+}
+
+func (st *Staleness) threshold(it *database.Item) int {
+	if it.Info != nil {
+		if days, ok := st.Days[strings.ToLower(it.Info.Category)]; ok {
+			return days
+		}
+	}
+	if days, ok := st.Days[""]; ok {
+		return days
+	}
+	return staleDays
+}
+
+func (st *Staleness) isStale(it *database.Item, now time.Time) bool {
+	if it.Price == nil || len(it.Price.NAReasons) != 0 {
+		return false
+	}
+	return it.Updated.IsZero() || it.Updated.Before(now.AddDate(0, 0, -st.threshold(it)))
+}
+
+// staleness returns a copy of the stale price settings of the guild with defaults for unset ones. Commands get
+// the copy, so the settings are not read by the processor while they are changed.
+func (g *Guild) staleness() *Staleness {
+	st := &Staleness{Days: map[string]int{"": staleDays}, Mode: StaleFlag}
+	if g.Stale == nil {
+		return st
+	}
+	for category, days := range g.Stale.Days {
+		st.Days[category] = days
+	}
+	if g.Stale.Mode != "" {
+		st.Mode = g.Stale.Mode
+	}
+	if st.Mode == staleLegacyWeight {
+		st.Mode = StaleConfidence
+	}
+	return st
+}
+
+// stalePrice reports if itemPrice would use the stale shared price of the item. Seen stale items are remembered
+// for flagging the current estimate.
+func (p *Processor) stalePrice(race database.Race, id string, pr *pricing) bool {
+	if pr == nil || pr.stale == nil {
+		return false
+	}
+	if _, ok := pr.personal(id); ok {
+		return false
+	}
+	if p.vendorPriced(race, id, pr) {
+		return false
+	}
+
+	if !pr.stale.isStale(p.db.Items[race][id], time.Now()) {
+		return false
+	}
+	if pr.seen != nil {
+		pr.seen[id] = true
+	}
+	return true
+}
+
+// staleEstimate computes the recipe price and notes about stale prices used in it.
+func (p *Processor) staleEstimate(race database.Race, ct database.CraftType, id string, pr *pricing, lang string) (*utility.TheInt, string) {
+	if pr.stale == nil {
+		return p.priceByRecipe(race, ct, id, true, pr), ""
+	}
+
+	seen := &pricing{book: pr.book, stale: pr.stale, seen: map[string]bool{}}
+	price := p.priceByRecipe(race, ct, id, true, seen)
+	if len(seen.seen) == 0 || pr.stale.Mode == StaleExclude {
+		return price, ""
+	}
+
+	note := tr(lang, "stale.flag", len(seen.seen))
+	if pr.stale.Mode == StaleConfidence && len(price.NAReasons) == 0 && price.Value > 0 {
+		fresh := p.priceByRecipe(race, ct, id, true, &pricing{book: pr.book, stale: pr.stale, fresh: true})
+		note += ", " + tr(lang, "stale.confidence", fresh.Value*100/price.Value)
+	}
+	return price, note
+}
+
+// Stale lists shared prices which are stale by cmd.Stale, the oldest first.
+func (p *Processor) Stale(cmd Command) string {
+	st := cmd.Stale
+	if st == nil {
+		st = &Staleness{}
+	}
+
+	now := time.Now()
+	items := []*database.Item{}
+	for _, it := range p.db.Items[cmd.Race] {
+		if st.isStale(it, now) && !p.vendorPriced(cmd.Race, it.ID, nil) {
+			items = append(items, it)
+		}
+	}
+	if len(items) == 0 {
+		return tr(cmd.Lang, "stale.none")
+	}
+	sortByUpdated(items)
+
+	rv := tr(cmd.Lang, "stale.title", len(items))
+	for i, it := range items {
+		if i >= staleLimit {
+			rv += "\n\t" + tr(cmd.Lang, "stale.more", len(items)-i)
+			break
+		}
+		rv += "\n\t" + tr(cmd.Lang, "digest.staleline", it.LocalName(cmd.Lang), it.Price.Value, updatedDate(it, cmd.Lang))
+	}
+	return rv
+}
+
+func sortByUpdated(items []*database.Item) {
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].Updated.Equal(items[j].Updated) {
+			return items[i].Updated.Before(items[j].Updated)
+		}
+		return items[i].Name < items[j].Name
+	})
+}
+
+// staleCommand lists stale prices or configures them: stale [list|config|days <days> [category]|mode <mode>].
+// Guild settings are changed by administrators only.
+func (d *Discord) staleCommand(s *discordgo.Session, m *discordgo.MessageCreate, g *Guild, race database.Race, isRaceSelected bool, args string) {
+	st := g.staleness()
+	params := strings.Fields(args)
+	op := ""
+	if len(params) > 0 {
+		op = strings.ToLower(params[0])
+	}
+
+	switch op {
+	case "", "list":
+		if !isRaceSelected {
+			msg := tr(g.Language, "race.first", g.prefix())
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+		g.cmdc <- Command{
+			Action: StaleList,
+			Race:   race,
+			Stale:  st,
+			Lang:   g.Language,
+			Out:    g.outc,
+		}
+		msg := <-g.outc
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+		return
+	case "config":
+		categories := []string{}
+		for category, days := range st.Days {
+			if category != "" {
+				categories = append(categories, category+": "+strconv.Itoa(days))
+			}
+		}
+		sort.Strings(categories)
+		msg := tr(g.Language, "stale.config", st.threshold(&database.Item{}), strings.Join(categories, ", "), st.Mode)
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+		return
+	case "days", "mode":
+	default:
+		msg := tr(g.Language, "stale.usage", g.prefix(), strings.Join(staleModes, "|"))
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+		return
+	}
+
+	if m.GuildID != "" && !d.isAdmin(s, m) {
+		msg := tr(g.Language, "stale.admin")
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+		return
+	}
+
+	var msg string
+	if op == "days" {
+		days := 0
+		if len(params) > 1 {
+			days = atoi(params[1])
+		}
+		if days <= 0 {
+			msg = tr(g.Language, "stale.usage", g.prefix(), strings.Join(staleModes, "|"))
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+		category := strings.ToLower(strings.Join(params[2:], " "))
+		d.update(m, func(g *Guild) {
+			st := g.staleness()
+			st.Days[category] = days
+			g.Stale = st
+			d.SaveNeeded = true
+		})
+		if category == "" {
+			category = tr(g.Language, "stale.other")
+		}
+		msg = tr(g.Language, "stale.days", category, days)
+	} else {
+		mode := ""
+		if len(params) > 1 {
+			for _, md := range staleModes {
+				if strings.ToLower(params[1]) == md {
+					mode = md
+				}
+			}
+		}
+		if mode == "" {
+			msg = tr(g.Language, "stale.usage", g.prefix(), strings.Join(staleModes, "|"))
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+		d.update(m, func(g *Guild) {
+			st := g.staleness()
+			st.Mode = mode
+			g.Stale = st
+			d.SaveNeeded = true
+		})
+		msg = tr(g.Language, "stale.mode", mode)
+	}
+
+	utility.SendMonitored(s, &m.ChannelID, &msg)
+}
@@ -0,0 +1,41 @@
+package input
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mebaranov/aioncraft/database"
+	"github.com/mebaranov/aioncraft/utility"
+)
+
+func TestStaleExclude(t *testing.T) {
+	old := time.Now().AddDate(0, 0, -staleDays-1)
+	tests := []struct {
+		name    string
+		updated time.Time
+		known   bool
+	}{
+		{"fresh", time.Now(), true},
+		{"old", old, false},
+		{"unknown age", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := database.New()
+			db.Items[database.Elyos]["O"] = &database.Item{ID: "O", Name: "Ore", Price: &utility.TheInt{Value: 10}, Updated: tt.updated}
+			p := NewProcessor(db)
+			pr := &pricing{stale: &Staleness{Days: map[string]int{"": staleDays}, Mode: StaleExclude}}
+			if got := p.itemPrice(database.Elyos, "O", pr); (len(got.NAReasons) == 0) != tt.known {
+				t.Errorf("itemPrice() = %v, want known %v", got, tt.known)
+			}
+		})
+	}
+}
+
+func TestStalenessLegacyMode(t *testing.T) {
+	g := &Guild{Stale: &Staleness{Mode: "weight"}}
+	if got := g.staleness().Mode; got != StaleConfidence {
+		t.Errorf("staleness().Mode = %q, want %q", got, StaleConfidence)
+	}
+}
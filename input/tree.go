@@ -0,0 +1,126 @@
+package input
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/google/martian/v3/log"
+	"github.com/mebaranov/aioncraft/database"
+	"github.com/mebaranov/aioncraft/utility"
+)
+
+// TreeNode is an item of a craft tree. Recipe is nil for items which are bought, otherwise the item is crafted
+// Crafts times with the Recipe of the Craft and Children are its ingredients.
+type TreeNode struct {
+	ItemID   string
+	Name     string
+	Quantity int
+	Craft    database.CraftType
+	Recipe   *database.Recipe
+	Crafts   int
+	// Cost is the price of the whole quantity: bought items at their price, crafted ones as the sum of children.
+	Cost     *utility.TheInt
+	Children []*TreeNode
+}
+
+// Tree builds the craft tree of a single craft of the recipe. Ingredients are crafted and bought the same way
+// priceByRecipe does, but whole crafts are counted, so leftovers of bulk recipes are paid for. Stale may be nil.
+func (p *Processor) Tree(race database.Race, ct database.CraftType, recID string, book map[string]int, stale *Staleness, lang string) *TreeNode {
+	rec := p.db.Recipes[race][ct][recID]
+	return p.craftNode(race, ct, rec, rec.Count, &pricing{book: book, stale: stale}, lang, map[string]bool{})
+}
+
+// Trees builds trees of every craft having a recipe for the item with the name.
+func (p *Processor) Trees(race database.Race, name string, book map[string]int, stale *Staleness, lang string) []*TreeNode {
+	rv := []*TreeNode{}
+	for _, item := range p.db.Items[race] {
+		if !matchAny(item, func(n string) bool { return strings.EqualFold(n, name) }) {
+			continue
+		}
+		for _, ct := range database.Crafts {
+			if rec := p.db.RecipeByItem(race, ct, item.ID); rec != nil {
+				rv = append(rv, p.Tree(race, ct, rec.ID, book, stale, lang))
+			}
+		}
+	}
+
+	sort.SliceStable(rv, func(i, j int) bool {
+		return rv[i].ItemID < rv[j].ItemID
+	})
+	return rv
+}
+
+func (p *Processor) craftNode(race database.Race, ct database.CraftType, rec *database.Recipe, quantity int, pr *pricing, lang string, path map[string]bool) *TreeNode {
+	rv := &TreeNode{
+		ItemID:   rec.ItemID,
+		Name:     p.itemName(race, rec.ItemID, lang),
+		Quantity: quantity,
+		Craft:    ct,
+		Recipe:   rec,
+		Crafts:   (quantity + rec.Count - 1) / rec.Count,
+		Cost:     &utility.TheInt{},
+	}
+
+	path[rec.ID] = true
+	defer delete(path, rec.ID)
+
+	ids := make([]string, 0, len(rec.Items))
+	for id := range rec.Items {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		need := rec.Items[id] * rv.Crafts
+
+		var child *TreeNode
+		if subCt, sub := p.subRecipe(race, ct, id, pr, path); sub != nil && sub.Count > 0 {
+			child = p.craftNode(race, subCt, sub, need, pr, lang, path)
+		} else {
+			child = &TreeNode{
+				ItemID:   id,
+				Name:     p.itemName(race, id, lang),
+				Quantity: need,
+				Craft:    ct,
+				Cost:     p.itemPrice(race, id, pr).Mul(need),
+			}
+		}
+
+		rv.Cost = rv.Cost.Plus(child.Cost)
+		rv.Children = append(rv.Children, child)
+	}
+
+	return rv
+}
+
+// itemName returns the local name of the item or its ID if the item is not known.
+func (p *Processor) itemName(race database.Race, id string, lang string) string {
+	if it, ok := p.db.Items[race][id]; ok && it.Name != "" {
+		return it.LocalName(lang)
+	}
+	return id
+}
+
+// Graph renders craft trees of the item cmd.Item in cmd.Format. Nothing is returned if the item has no recipes or
+// the tree is too large for an image.
+func (p *Processor) Graph(cmd Command) string {
+	lang := cmd.Lang
+	if cmd.Format == FormatPNG {
+		// The image font has ASCII glyphs only
+		lang = database.DefaultLocale
+	}
+
+	trees := p.Trees(cmd.Race, cmd.Item, cmd.Book, cmd.Stale, lang)
+	if len(trees) == 0 {
+		return ""
+	}
+
+	rv, err := RenderGraph(cmd.Format, trees, lang)
+	if err != nil {
+		if err != errTreeTooLarge {
+			log.Errorf("%v", err)
+		}
+		return ""
+	}
+	return rv
+}
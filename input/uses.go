@@ -0,0 +1,35 @@
+package input
+
+import (
+	"fmt"
+)
+
+// usesLimit caps the amount of recipes listed by '/c uses'.
+const usesLimit = 40
+
+// Uses lists recipes consuming the item directly or through intermediate crafts.
+func (p *Processor) Uses(cmd Command) string {
+	it := p.findItem(cmd.Race, cmd.Item)
+	if it == nil {
+		return tr(cmd.Lang, "item.notfound", cmd.Item)
+	}
+
+	uses := p.db.Uses(it.ID, 0)
+	if len(uses) == 0 {
+		return tr(cmd.Lang, "uses.none", it.LocalName(cmd.Lang))
+	}
+
+	rv := tr(cmd.Lang, "uses.title", it.LocalName(cmd.Lang), len(uses))
+	for i, use := range uses {
+		if i >= usesLimit {
+			rv += "\n" + tr(cmd.Lang, "uses.more", len(uses)-i)
+			break
+		}
+
+		name := p.productName(use.Race, use.Recipe, cmd.Lang)
+		craft := tr(cmd.Lang, "craft."+use.Craft.String())
+		rv += "\n" + tr(cmd.Lang, "uses.line", use.Depth, use.Race, craft, use.Recipe.Level, name, fmt.Sprintf("%.4g", use.Quantity))
+	}
+
+	return rv
+}
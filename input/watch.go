@@ -0,0 +1,396 @@
+package input
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/google/martian/v3/log"
+	"github.com/mebaranov/aioncraft/database"
+	"github.com/mebaranov/aioncraft/utility"
+)
+
+// Kinds of watched values.
+const (
+	WatchCost   = "cost"
+	WatchMargin = "margin"
+	WatchPrice  = "price"
+)
+
+var (
+	watchRegex        = regexp.MustCompile(`(?i)^(.+?)\s+(cost|margin|price)\s*([<>])\s*(-?\S+)$`)
+	channelMentionRex = regexp.MustCompile(`^<#(\d+)>$`)
+)
+
+// Watch alerts User when the value of the item crosses Value. Cost is the craft cost estimate, margin is the item
+// price minus the cost. Alerts go to Channel, or to direct messages if it is empty. Triggered is set while the
+// condition holds, so every crossing is reported once.
+type Watch struct {
+	ID        int
+	Race      database.Race
+	Item      string
+	ItemID    string
+	Kind      string
+	Op        string
+	Value     int
+	Channel   string
+	User      string
+	Triggered bool
+}
+
+// PriceListener is notified when prices of the race change. Items are the changed ones and products made of them.
+// It is called from the processor goroutine, so it must not wait for commands.
+type PriceListener interface {
+	PricesChanged(race database.Race, items []string)
+}
+
+func (p *Processor) notify(race database.Race, ids ...string) {
+	if len(p.listeners) == 0 {
+		return
+	}
+	items := p.affected(race, ids)
+	for _, l := range p.listeners {
+		l.PricesChanged(race, items)
+	}
+}
+
+// affected returns the items and products made of them directly or through intermediate crafts, as costs and
+// margins of the products change with prices of the items.
+func (p *Processor) affected(race database.Race, ids []string) []string {
+	index := p.db.UsesIndex(race)
+	seen := map[string]bool{}
+	for queue := ids; len(queue) > 0; {
+		id := queue[0]
+		queue = queue[1:]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		for _, ref := range index[id] {
+			queue = append(queue, ref.Recipe.ItemID)
+		}
+	}
+
+	rv := make([]string, 0, len(seen))
+	for id := range seen {
+		rv = append(rv, id)
+	}
+	sort.Strings(rv)
+	return rv
+}
+
+// CheckWatch evaluates cmd.Watch and returns an alert if its condition became true. Conditions on unknown prices
+// are false. The item of a new watch is looked up by its name. Cmd.Watch must not be shared with other goroutines,
+// its new Triggered state and item are read by the caller after the reply.
+func (p *Processor) CheckWatch(cmd Command) string {
+	w := cmd.Watch
+	if w.ItemID == "" {
+		it := p.findItem(cmd.Race, w.Item)
+		if it == nil {
+			return tr(cmd.Lang, "item.notfound", w.Item)
+		}
+		w.ItemID, w.Item = it.ID, it.Name
+	}
+
+	value := p.watchValue(cmd.Race, w, newPricing(cmd))
+	met := len(value.NAReasons) == 0 && ((w.Op == "<" && value.Value < w.Value) || (w.Op == ">" && value.Value > w.Value))
+	if !met || w.Triggered {
+		w.Triggered = met
+		return ""
+	}
+
+	w.Triggered = true
+	return tr(cmd.Lang, "watch.alert", w.ID, p.itemName(cmd.Race, w.ItemID, cmd.Lang), tr(cmd.Lang, "watch."+w.Kind), value.Value, w.Op, w.Value)
+}
+
+func (p *Processor) watchValue(race database.Race, w *Watch, pr *pricing) *utility.TheInt {
+	if w.Kind == WatchPrice {
+		return p.itemPrice(race, w.ItemID, pr)
+	}
+
+	var cost *utility.TheInt
+	for _, ct := range database.Crafts {
+		if rec := p.db.RecipeByItem(race, ct, w.ItemID); rec != nil {
+			if price := p.priceByRecipe(race, ct, rec.ID, false, pr); cost == nil || cheaper(price, cost) {
+				cost = price
+			}
+		}
+	}
+	if cost == nil {
+		return utility.NewInt(0, w.Item)
+	}
+	if w.Kind == WatchCost {
+		return cost
+	}
+
+	return p.itemPrice(race, w.ItemID, pr).Plus(&utility.TheInt{Value: -cost.Value, NAReasons: cost.NAReasons})
+}
+
+// PricesChanged queues checking watches on the items. Items wait in the pending set until the watch loop takes
+// them, so a signal is dropped only when one is already queued.
+func (d *Discord) PricesChanged(race database.Race, items []string) {
+	d.pendingMu.Lock()
+	if d.pending == nil {
+		d.pending = map[database.Race]map[string]bool{}
+	}
+	if d.pending[race] == nil {
+		d.pending[race] = map[string]bool{}
+	}
+	for _, id := range items {
+		d.pending[race][id] = true
+	}
+	d.pendingMu.Unlock()
+
+	select {
+	case d.changes <- true:
+	default:
+	}
+}
+
+func (d *Discord) watchLoop() {
+	for range d.changes {
+		d.pendingMu.Lock()
+		pending := d.pending
+		d.pending = nil
+		d.pendingMu.Unlock()
+
+		for race, items := range pending {
+			d.checkWatches(race, items)
+		}
+	}
+}
+
+// watchCheck is a copy of the watch evaluated without holding the lock.
+type watchCheck struct {
+	watch *Watch
+	check Watch
+	cmd   Command
+}
+
+// checkWatches evaluates watches of the race on the items. Watches made in direct messages use personal prices.
+// Watches and books are copied under the lock, so handlers may change them while the processor is busy.
+func (d *Discord) checkWatches(race database.Race, items map[string]bool) {
+	checks := []*watchCheck{}
+	add := func(g *Guild, book map[string]int) {
+		for _, w := range g.Watches {
+			if w.Race != race || !items[w.ItemID] {
+				continue
+			}
+			checks = append(checks, &watchCheck{
+				watch: w,
+				check: *w,
+				cmd:   Command{Action: WatchCheck, Race: race, Book: book, Stale: g.staleness(), Lang: g.Language},
+			})
+		}
+	}
+
+	d.mu.Lock()
+	for _, g := range d.Guilds {
+		add(g, nil)
+	}
+	for _, u := range d.Users {
+		var book map[string]int
+		if u.Book[race] != nil {
+			book = copyCounts(u.Book[race])
+		}
+		add(u, book)
+	}
+	d.mu.Unlock()
+
+	for _, c := range checks {
+		out := make(chan string, 1)
+		c.cmd.Watch, c.cmd.Out = &c.check, out
+		d.cmdc <- c.cmd
+		msg := <-out
+
+		d.mu.Lock()
+		if c.watch.Triggered != c.check.Triggered {
+			c.watch.Triggered = c.check.Triggered
+			d.SaveNeeded = true
+		}
+		d.mu.Unlock()
+		if msg != "" {
+			d.alert(&c.check, msg)
+		}
+	}
+}
+
+func (d *Discord) alert(w *Watch, msg string) {
+	channel := w.Channel
+	if channel == "" {
+		ch, err := d.s.UserChannelCreate(w.User)
+		if err != nil {
+			log.Errorf("Could not open direct messages with %v. Error: %v", w.User, err)
+			return
+		}
+		channel = ch.ID
+	} else {
+		msg = fmt.Sprintf("<@%v> %v", w.User, msg)
+	}
+
+	utility.SendMonitored(d.s, &channel, &msg)
+}
+
+func (d *Discord) watch(s *discordgo.Session, m *discordgo.MessageCreate, g *Guild, race database.Race, isRaceSelected bool, book map[string]int, args string) {
+	params := strings.SplitN(strings.TrimSpace(args), " ", 2)
+	switch strings.ToLower(params[0]) {
+	case "", "list":
+		msg := tr(g.Language, "watch.none")
+		if len(g.Watches) > 0 {
+			msg = tr(g.Language, "watch.title")
+			for _, w := range g.Watches {
+				to := tr(g.Language, "watch.dm")
+				if w.Channel != "" {
+					to = "<#" + w.Channel + ">"
+				}
+				msg += "\n\t" + tr(g.Language, "watch.line", w.ID, w.Item, tr(g.Language, "watch."+w.Kind), w.Op, w.Value, w.Race.String(), to)
+			}
+		}
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+	case "remove":
+		if !d.checkPermission(s, m, g, PermWatch) {
+			return
+		}
+		id := -1
+		if len(params) == 2 {
+			id = atoi(strings.TrimPrefix(strings.TrimSpace(params[1]), "#"))
+		}
+		for _, w := range g.Watches {
+			if w.ID != id {
+				continue
+			}
+			if w.User != m.Author.ID && !d.isAdmin(s, m) {
+				msg := tr(g.Language, "watch.denied", id)
+				utility.SendMonitored(s, &m.ChannelID, &msg)
+				return
+			}
+
+			d.update(m, func(g *Guild) {
+				for i, w := range g.Watches {
+					if w.ID == id {
+						g.Watches = append(g.Watches[:i], g.Watches[i+1:]...)
+						d.SaveNeeded = true
+						break
+					}
+				}
+			})
+			msg := tr(g.Language, "watch.removed", id)
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+		msg := tr(g.Language, "watch.notfound", params[len(params)-1])
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+	default:
+		if !isRaceSelected {
+			msg := tr(g.Language, "race.first", g.prefix())
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+		if !d.checkPermission(s, m, g, PermWatch) {
+			return
+		}
+		w, ok := parseWatch(args, m.ChannelID)
+		if !ok {
+			msg := tr(g.Language, "watch.usage", g.prefix())
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+		if w.Channel != "" && w.Channel != m.ChannelID && !inGuild(s, w.Channel, m.GuildID) {
+			msg := tr(g.Language, "watch.channel", w.Channel)
+			utility.SendMonitored(s, &m.ChannelID, &msg)
+			return
+		}
+		w.Race, w.User = race, m.Author.ID
+		for _, other := range g.Watches {
+			if other.ID >= w.ID {
+				w.ID = other.ID + 1
+			}
+		}
+		if w.ID == 0 {
+			w.ID = 1
+		}
+
+		g.cmdc <- Command{
+			Action: WatchCheck,
+			Race:   race,
+			Watch:  w,
+			Book:   book,
+			Stale:  g.staleness(),
+			Lang:   g.Language,
+			Out:    g.outc,
+		}
+		alert := <-g.outc
+		if w.ItemID == "" {
+			utility.SendMonitored(s, &m.ChannelID, &alert)
+			return
+		}
+
+		d.update(m, func(g *Guild) {
+			// Another watch may have taken the ID meanwhile
+			for _, other := range g.Watches {
+				if other.ID >= w.ID {
+					w.ID = other.ID + 1
+				}
+			}
+			g.Watches = append(g.Watches, w)
+			d.SaveNeeded = true
+		})
+		msg := tr(g.Language, "watch.added", w.ID, w.Item, tr(g.Language, "watch."+w.Kind), w.Op, w.Value)
+		if alert != "" {
+			msg += "\n" + alert
+		}
+		utility.SendMonitored(s, &m.ChannelID, &msg)
+	}
+}
+
+// inGuild reports if the channel belongs to the guild. Channels missing in the state are requested.
+func inGuild(s *discordgo.Session, channelID string, guildID string) bool {
+	ch, err := s.State.Channel(channelID)
+	if err != nil {
+		if ch, err = s.Channel(channelID); err != nil {
+			return false
+		}
+	}
+	return ch.GuildID == guildID
+}
+
+// parseWatch reads '<item name> cost|margin|price <|> <value> [here|dm|#channel]'. Alerts go to the channel by default.
+func parseWatch(args string, channel string) (*Watch, bool) {
+	fields := strings.Fields(args)
+	if len(fields) > 0 {
+		last := fields[len(fields)-1]
+		switch strings.ToLower(last) {
+		case "here":
+			fields = fields[:len(fields)-1]
+		case "dm":
+			channel, fields = "", fields[:len(fields)-1]
+		default:
+			if mention := channelMentionRex.FindStringSubmatch(last); mention != nil {
+				channel, fields = mention[1], fields[:len(fields)-1]
+			}
+		}
+	}
+
+	tmp := watchRegex.FindStringSubmatch(strings.Join(fields, " "))
+	if tmp == nil {
+		return nil, false
+	}
+	value, err := strconv.Atoi(tmp[4])
+	if err != nil {
+		var ok bool
+		if value, ok = parseKinah(tmp[4]); !ok {
+			return nil, false
+		}
+	}
+
+	return &Watch{
+		Item:    strings.TrimSpace(tmp[1]),
+		Kind:    strings.ToLower(tmp[2]),
+		Op:      tmp[3],
+		Value:   value,
+		Channel: channel,
+	}, true
+}
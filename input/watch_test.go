@@ -0,0 +1,66 @@
+package input
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mebaranov/aioncraft/database"
+)
+
+func TestInGuild(t *testing.T) {
+	s := testSession(t)
+	if !inGuild(s, "c1", "g1") {
+		t.Errorf("inGuild(c1, g1) = false, want true")
+	}
+	if inGuild(s, "c2", "g1") {
+		t.Errorf("inGuild(c2, g1) = true, want false")
+	}
+}
+
+func TestParseWatch(t *testing.T) {
+	tests := []struct {
+		args    string
+		item    string
+		channel string
+		ok      bool
+	}{
+		{"Steel Ingot margin>0", "Steel Ingot", "c1", true},
+		{"Steel Ingot price < 5k dm", "Steel Ingot", "", true},
+		{"Steel Ingot cost > 100 <#42>", "Steel Ingot", "42", true},
+		{"Steel Ingot cheap", "", "", false},
+	}
+
+	for _, tt := range tests {
+		w, ok := parseWatch(tt.args, "c1")
+		if ok != tt.ok || (ok && (w.Item != tt.item || w.Channel != tt.channel)) {
+			t.Errorf("parseWatch(%q) = %+v, %v, want item %q to %q", tt.args, w, ok, tt.item, tt.channel)
+		}
+	}
+}
+
+func TestAffected(t *testing.T) {
+	db := database.New()
+	db.Recipes[database.Elyos][database.Weapon]["rIngot"] = &database.Recipe{ID: "rIngot", ItemID: "ingot", Count: 1, Items: map[string]int{"ore": 2}}
+	db.Recipes[database.Elyos][database.Weapon]["rBlade"] = &database.Recipe{ID: "rBlade", ItemID: "blade", Count: 1, Items: map[string]int{"ingot": 3, "wood": 1}}
+	db.Recipes[database.Elyos][database.Cooking]["rStew"] = &database.Recipe{ID: "rStew", ItemID: "stew", Count: 1, Items: map[string]int{"meat": 1}}
+	p := NewProcessor(db)
+
+	if got, want := p.affected(database.Elyos, []string{"ore"}), []string{"blade", "ingot", "ore"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("affected(ore) = %v, want %v", got, want)
+	}
+	if got, want := p.affected(database.Elyos, []string{"meat", "wood"}), []string{"blade", "meat", "stew", "wood"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("affected(meat, wood) = %v, want %v", got, want)
+	}
+}
+
+func TestMergeCounts(t *testing.T) {
+	live := map[string]int{"ore": 5, "wood": 2, "meat": 1}
+	before := map[string]int{"ore": 5, "wood": 2}
+	after := map[string]int{"ore": 7, "salt": 1}
+	mergeCounts(live, before, after)
+
+	// Meat was added to the kept counts meanwhile, so it stays
+	if want := map[string]int{"ore": 7, "salt": 1, "meat": 1}; !reflect.DeepEqual(live, want) {
+		t.Errorf("mergeCounts() = %v, want %v", live, want)
+	}
+}
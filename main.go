@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"net"
 	"os"
+	"strings"
 	"time"
 
 	"cloud.google.com/go/storage"
@@ -18,15 +19,6 @@ import (
 	"github.com/mebaranov/aioncraft/scrapper"
 )
 
-var paths = map[database.CraftType]string{
-	database.Alchemy:    "data/alchemy.json",
-	database.Armor:      "data/armorsmith.json",
-	database.Cooking:    "data/cooking.json",
-	database.Handicraft: "data/handiwork.json",
-	database.Tailor:     "data/tailoring.json",
-	database.Weapon:     "data/weaponsmith.json",
-}
-
 var dbPath = "data/database.json"
 var discPath = "data/discord.json"
 
@@ -46,14 +38,33 @@ func main() {
 		gcsBucket string
 		cli       bool
 		verbose   bool
+		locales   string
+		codexURL  string
+		codexDir  string
+		rate      float64
+		workers   int
 	)
 
 	flag.StringVar(&discToken, "t", "", "Bot token")
 	flag.BoolVar(&cli, "cli", false, "Use CLI")
 	flag.StringVar(&gcsBucket, "b", "", "GCS Bucket")
 	flag.BoolVar(&verbose, "v", false, "Verbose logs")
+	flag.StringVar(&locales, "l", "", "Comma separated additional locales for item names (de,fr,ru,ko)")
+	flag.StringVar(&codexURL, "codex", scrapper.DefaultBaseURL, "Codex base URL")
+	flag.StringVar(&codexDir, "codex-dir", "", "Directory with saved codex pages to use instead of the site")
+	flag.Float64Var(&rate, "rate", scrapper.DefaultRate, "Codex requests per second")
+	flag.IntVar(&workers, "workers", scrapper.DefaultWorkers, "Concurrent codex requests")
 	flag.Parse()
 
+	switch flag.Arg(0) {
+	case "diff":
+		os.Exit(diffCommand(flag.Args()[1:]))
+	case "ingest":
+		os.Exit(ingestCommand(flag.Args()[1:]))
+	case "tree":
+		os.Exit(treeCommand(flag.Args()[1:]))
+	}
+
 	if verbose {
 		log.SetLevel(log.Info)
 	}
@@ -65,10 +76,23 @@ func main() {
 		gcsBucket = os.Getenv("GCS_BUCKET")
 	}
 
+	httpSrc := scrapper.NewHTTPSource(codexURL)
+	httpSrc.Requester = scrapper.NewRequesterWithRate(rate)
+	var src scrapper.Source = httpSrc
+	if codexDir != "" {
+		src = scrapper.NewDirSource(codexDir)
+	}
+
 	m := &MainStr{
-		scrap: scrapper.New(),
+		scrap: scrapper.NewWithSource(src),
 		ctx:   context.Background(),
 	}
+	m.scrap.Workers = workers
+	m.scrap.Checkpoint = func() {
+		if err := m.SaveDatabase(); err != nil {
+			log.Errorf("Could not save naming progress: %v", err)
+		}
+	}
 	var err error
 
 	if gcsBucket != "" {
@@ -88,14 +112,48 @@ func main() {
 		return
 	}
 
-	if m.db.CurState != database.Named {
+	checkCrafts(m.db)
+
+	extraLocales := []string{}
+	for _, l := range strings.Split(locales, ",") {
+		if l = strings.TrimSpace(l); l != "" {
+			extraLocales = append(extraLocales, l)
+		}
+	}
+
+	switch flag.Arg(0) {
+	case "":
+	case "refresh":
+		summary, err := m.Refresh(extraLocales)
+		if err != nil {
+			log.Errorf("Could not refresh database: %v", err)
+			os.Exit(1)
+		}
+		fmt.Print(summary)
+		return
+	case "describe":
+		if err := m.Describe(); err != nil {
+			log.Errorf("Could not save described database: %v", err)
+			os.Exit(1)
+		}
+		return
+	default:
+		log.Errorf("Unknown command: %v", flag.Arg(0))
+		os.Exit(2)
+	}
+
+	if m.db.CurState < database.Named || len(extraLocales) > 0 {
 		log.Infof("Naming %v + %v items", len(m.db.Items[database.Elyos]), len(m.db.Items[database.Asmodian]))
-		m.scrap.Name(m.db.Items[database.Elyos])
-		m.scrap.Name(m.db.Items[database.Asmodian])
-		m.db.CurState = database.Named
+		m.scrap.Name(extraLocales, m.db.Items[database.Elyos], m.db.Items[database.Asmodian])
+		if m.db.CurState < database.Named {
+			m.db.CurState = database.Named
+		}
 
 		m.SaveDatabase()
 	}
+	if m.db.CurState < database.Described {
+		log.Infof("Item and recipe details are not loaded. Run 'describe' to load them from the codex")
+	}
 
 	m.processor = input.NewProcessor(m.db)
 
@@ -191,27 +249,85 @@ func (m *MainStr) InitDatabase() error {
 		log.Errorf("DB could not be initialized from file: %v", err)
 	}
 
-	m.db = database.New()
-	for t, p := range paths {
-		file, err := os.Open(p)
+	m.db = m.scrapDumps()
+
+	m.db.CurState = database.Scrapped
+	err := m.SaveDatabase()
+	log.Infof("DB scrapped and saved\n")
+
+	return err
+}
+
+func (m *MainStr) scrapDumps() *database.Database {
+	db := database.New()
+	dumps, err := findDumps(dataDir)
+	if err != nil {
+		log.Errorf("Could not find dumps. Error: %v", err)
+		return db
+	}
+	if missing := missingCrafts(dumps); len(missing) > 0 {
+		log.Errorf("No dumps for crafts: %v", missing)
+	}
+
+	for _, d := range dumps {
+		data, err := ioutil.ReadFile(d.File)
 		if err != nil {
-			log.Errorf("Could not open file (%v). Error: %v", p, err)
+			log.Errorf("Could not read file with data (%v). Error: %v", d.File, err)
 			continue
 		}
 
-		data, err := ioutil.ReadAll(file)
+		items := map[database.Race]map[string]*database.Item{}
+		recs := map[database.Race]map[string]*database.Recipe{}
+		for _, race := range database.Races {
+			items[race], recs[race] = map[string]*database.Item{}, map[string]*database.Recipe{}
+			if d.hasRace(race) {
+				items[race], recs[race] = db.Items[race], db.Recipes[race][d.Craft]
+			}
+		}
+
+		report, err := m.scrap.Scrap(data, items[database.Elyos], recs[database.Elyos], items[database.Asmodian], recs[database.Asmodian])
 		if err != nil {
-			log.Errorf("Could not read file with data (%v). Error: %v", p, err)
+			log.Errorf("Could not scrap file (%v). Error: %v", d.File, err)
+			continue
+		}
+		if len(report.Skipped) > 0 || len(report.Duplicates) > 0 {
+			log.Errorf("Dump %v is ingested with problems. %v", d.File, report)
+		} else {
+			log.Infof("Dump %v ingested. %v", d.File, report)
 		}
+	}
+
+	return db
+}
 
-		m.scrap.Scrap(data, m.db.Items[database.Elyos], m.db.Recipes[database.Elyos][t], m.db.Items[database.Asmodian], m.db.Recipes[database.Asmodian][t])
+// Refresh merges recipes from the dump files into the loaded database, names new items and saves the result.
+func (m *MainStr) Refresh(locales []string) (*database.MergeSummary, error) {
+	old, err := m.db.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("Could not copy database. Error: %v", err)
 	}
 
-	m.db.CurState = database.Scrapped
-	err := m.SaveDatabase()
-	log.Infof("DB scrapped and saved\n")
+	summary := m.db.Merge(m.scrapDumps())
+	m.scrap.Name(locales, m.db.Items[database.Elyos], m.db.Items[database.Asmodian])
+	m.describe()
+	if diff := database.Compare(old, m.db); !diff.Empty() {
+		m.db.LastDiff = diff
+	}
 
-	return err
+	return summary, m.SaveDatabase()
+}
+
+// Describe loads grades, categories and vendor prices of items and sources of recipes from the codex and saves the
+// database. Only items and recipes without details are requested, so an interrupted run can be continued.
+func (m *MainStr) Describe() error {
+	m.describe()
+	return m.SaveDatabase()
+}
+
+func (m *MainStr) describe() {
+	m.scrap.DescribeItems(m.db.Items[database.Elyos], m.db.Items[database.Asmodian])
+	m.scrap.DescribeRecipes(m.db.RecipeMaps()...)
+	m.db.CurState = database.Described
 }
 
 func (m *MainStr) InitDiscord(token string) error {
@@ -297,7 +413,7 @@ func (m *MainStr) Saver() {
 				log.Errorf("Could not save database: %v", err)
 			}
 		}
-		if m.discInp != nil && m.discInp.SaveNeeded {
+		if m.discInp != nil && m.discInp.NeedsSave() {
 			log.Infof("Saving discord")
 			err := m.SaveDiscord()
 			if err != nil {
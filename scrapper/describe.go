@@ -0,0 +1,237 @@
+package scrapper
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/martian/v3/log"
+	"github.com/mebaranov/aioncraft/database"
+)
+
+const recipePathFmt = "%s/recipe/%s/"
+
+// Rows of the info table on codex pages, e.g. <tr><td>Learned from</td><td>...</td></tr>.
+var (
+	sourceRow    = infoRow("Learned from|Acquired from|Source")
+	kinahRow     = infoRow("Price|Cost")
+	apRow        = infoRow("AP|Abyss Points")
+	tierRow      = infoRow("Required Skill|Skill")
+	workOrderRow = infoRow("Work Order")
+	tagRegex     = regexp.MustCompile(`<[^>]*>`)
+	spaceRegex   = regexp.MustCompile(`\s+`)
+)
+
+func infoRow(labels string) *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf(`(?is)<t[dh][^>]*>\s*(?:%s)\s*:?\s*</t[dh]>\s*<td[^>]*>(.*?)</td>`, labels))
+}
+
+// DescribeRecipes loads metadata of recipes which have none from their codex pages.
+func (s *Scrapper) DescribeRecipes(maps ...map[string]*database.Recipe) {
+	ids := []string{}
+	seen := map[string]bool{}
+	for _, recs := range maps {
+		for id, rec := range recs {
+			if rec.Info == nil && !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	sort.Strings(ids)
+	log.Infof("Describing %v recipes", len(ids))
+
+	results := make([]*database.RecipeInfo, len(ids))
+	s.pool(len(ids), func(i int) {
+		results[i] = s.fetchRecipeInfo(ids[i])
+	}, func(i int) {
+		if results[i] == nil {
+			return
+		}
+		for _, recs := range maps {
+			if rec, ok := recs[ids[i]]; ok {
+				info := *results[i]
+				rec.Info = &info
+			}
+		}
+	})
+}
+
+func (s *Scrapper) fetchRecipeInfo(id string) *database.RecipeInfo {
+	data, err := s.src.Get(fmt.Sprintf(recipePathFmt, Regions[database.DefaultLocale], id))
+	if err != nil {
+		log.Errorf("Could not load recipe data (%v). Error: %v", id, err)
+		return nil
+	}
+
+	info, err := s.recipeInfo(data)
+	if err != nil {
+		log.Errorf("%v (%v)", err, id)
+		return nil
+	}
+
+	return info
+}
+
+func (s *Scrapper) recipeInfo(page []byte) (*database.RecipeInfo, error) {
+	rv := &database.RecipeInfo{}
+	found := false
+
+	if v, ok := infoValue(page, sourceRow); ok {
+		rv.Source, found = v, true
+	}
+	if v, ok := infoValue(page, kinahRow); ok {
+		rv.Kinah, found = number(v), true
+	}
+	if v, ok := infoValue(page, apRow); ok {
+		rv.AP, found = number(v), true
+	}
+	if v, ok := infoValue(page, tierRow); ok {
+		rv.Tier, found = v, true
+	}
+	if v, ok := infoValue(page, workOrderRow); ok {
+		rv.WorkOrder, found = yes(v), true
+	}
+
+	if !found {
+		return nil, fmt.Errorf("No recipe info on the page")
+	}
+	return rv, nil
+}
+
+// infoValue returns the text of the info row without tags.
+func infoValue(page []byte, row *regexp.Regexp) (string, bool) {
+	tmp := row.FindSubmatch(page)
+	if len(tmp) != 2 {
+		return "", false
+	}
+
+	text := html.UnescapeString(tagRegex.ReplaceAllString(string(tmp[1]), " "))
+	return strings.TrimSpace(spaceRegex.ReplaceAllString(text, " ")), true
+}
+
+// number parses amounts like "12,500 Kinah". Zero is returned if there are no digits.
+func number(s string) int {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+
+	rv, _ := strconv.Atoi(digits)
+	return rv
+}
+
+func yes(s string) bool {
+	switch strings.ToLower(s) {
+	case "yes", "true", "1", "✓":
+		return true
+	}
+	return false
+}
+
+var (
+	gradeRegex     = regexp.MustCompile(`class="item_title item_grade_(\d+)"[^>]*id="item_name"`)
+	categoryRow    = infoRow("Category|Type")
+	vendorPriceRow = infoRow("Vendor Price|Buy Price|NPC Price")
+	tradeableRow   = infoRow("Tradeable|Tradable")
+	stackRow       = infoRow("Max Stack|Stack")
+)
+
+// Grades maps codex item_grade_N classes to grade names.
+var Grades = map[string]string{
+	"0": "Junk",
+	"1": "Common",
+	"2": "Superior",
+	"3": "Heroic",
+	"4": "Fabled",
+	"5": "Eternal",
+	"6": "Mythic",
+}
+
+// DescribeItems loads metadata of items which have none from their codex pages.
+func (s *Scrapper) DescribeItems(maps ...map[string]*database.Item) {
+	ids := []string{}
+	seen := map[string]bool{}
+	for _, items := range maps {
+		for id, item := range items {
+			if item.Info == nil && !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	sort.Strings(ids)
+	log.Infof("Describing %v items", len(ids))
+
+	results := make([]*database.ItemInfo, len(ids))
+	s.pool(len(ids), func(i int) {
+		results[i] = s.fetchItemInfo(ids[i])
+	}, func(i int) {
+		if results[i] == nil {
+			return
+		}
+		for _, items := range maps {
+			if item, ok := items[ids[i]]; ok {
+				info := *results[i]
+				item.Info = &info
+			}
+		}
+	})
+}
+
+func (s *Scrapper) fetchItemInfo(id string) *database.ItemInfo {
+	data, err := s.src.Get(fmt.Sprintf(itemPathFmt, Regions[database.DefaultLocale], id))
+	if err != nil {
+		log.Errorf("Could not load item data (%v). Error: %v", id, err)
+		return nil
+	}
+
+	info, err := s.itemInfo(data)
+	if err != nil {
+		log.Errorf("%v (%v)", err, id)
+		return nil
+	}
+
+	return info
+}
+
+// itemInfo parses the item page. The grade is taken from the item title, which has the item_grade_N class.
+func (s *Scrapper) itemInfo(page []byte) (*database.ItemInfo, error) {
+	if _, err := s.itemName(page); err != nil {
+		return nil, err
+	}
+
+	rv := &database.ItemInfo{}
+	if title := gradeRegex.FindSubmatch(page); len(title) == 2 {
+		if name, ok := Grades[string(title[1])]; ok {
+			rv.Grade = name
+		} else {
+			rv.Grade = "Grade " + string(title[1])
+		}
+	}
+	if v, ok := infoValue(page, categoryRow); ok {
+		rv.Category = v
+	}
+	if v, ok := infoValue(page, vendorPriceRow); ok {
+		rv.VendorPrice = number(v)
+	}
+	if v, ok := infoValue(page, tradeableRow); ok {
+		rv.Tradeable = yes(v)
+	}
+	if v, ok := infoValue(page, stackRow); ok {
+		rv.Stack = number(v)
+	}
+
+	return rv, nil
+}
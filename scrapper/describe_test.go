@@ -0,0 +1,118 @@
+package scrapper
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mebaranov/aioncraft/database"
+)
+
+func TestRecipeInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    *database.RecipeInfo
+		wantErr bool
+	}{
+		{"vendor", "usc/recipe/155001195/", &database.RecipeInfo{Source: "Lainita (NPC)", Kinah: 1200, Tier: "Alchemy (Novice)"}, false},
+		{"work order", "usc/recipe/155001045/", &database.RecipeInfo{Source: "Drop: Virago", AP: 3500, Tier: "Handicraft (Expert)", WorkOrder: true}, false},
+		{"no info", "usc/recipe/155006201/", nil, true},
+	}
+
+	src := NewDirSource("testdata/pages")
+	s := NewWithSource(src)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := src.Get(tt.path)
+			if err != nil {
+				t.Fatalf("Could not load fixture: %v", err)
+			}
+
+			got, err := s.recipeInfo(page)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("recipeInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("recipeInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeRecipes(t *testing.T) {
+	known := &database.RecipeInfo{Source: "Known"}
+	elyos := map[string]*database.Recipe{
+		"155001195": {ID: "155001195"},
+		"155006201": {ID: "155006201"},
+		"155001045": {ID: "155001045", Info: known},
+	}
+	asmodian := map[string]*database.Recipe{
+		"155001195": {ID: "155001195"},
+	}
+
+	NewWithSource(NewDirSource("testdata/pages")).DescribeRecipes(elyos, asmodian)
+
+	for _, recs := range []map[string]*database.Recipe{elyos, asmodian} {
+		if info := recs["155001195"].Info; info == nil || info.Kinah != 1200 {
+			t.Errorf("Recipe is not described: %+v", info)
+		}
+	}
+	if elyos["155001195"].Info == asmodian["155001195"].Info {
+		t.Errorf("Races share the same info")
+	}
+	if elyos["155001045"].Info != known {
+		t.Errorf("Described recipe was described again")
+	}
+	if info := elyos["155006201"].Info; info != nil {
+		t.Errorf("Recipe without info got %+v", info)
+	}
+}
+
+func TestItemInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    *database.ItemInfo
+		wantErr bool
+	}{
+		{"gathered", "usc/item/152000301/", &database.ItemInfo{Grade: "Common", Category: "Gatherable - Ore", Tradeable: true, Stack: 1000}, false},
+		{"vendor", "usc/item/152000905/", &database.ItemInfo{Grade: "Superior", Category: "Crafting Material", VendorPrice: 1500, Stack: 100}, false},
+		{"no info table", "usc/item/152020099/", &database.ItemInfo{Grade: "Superior"}, false},
+		{"missing name", "usc/item/999999999/", nil, true},
+	}
+
+	src := NewDirSource("testdata/pages")
+	s := NewWithSource(src)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := src.Get(tt.path)
+			if err != nil {
+				t.Fatalf("Could not load fixture: %v", err)
+			}
+
+			got, err := s.itemInfo(page)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("itemInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("itemInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeItems(t *testing.T) {
+	items := map[string]*database.Item{
+		"152000905": {ID: "152000905"},
+		"999999999": {ID: "999999999"},
+	}
+
+	NewWithSource(NewDirSource("testdata/pages")).DescribeItems(items)
+
+	if got := items["152000905"].VendorPrice(); got != 1500 {
+		t.Errorf("VendorPrice() = %v, want 1500", got)
+	}
+	if info := items["999999999"].Info; info != nil {
+		t.Errorf("Item without page got %+v", info)
+	}
+}
@@ -0,0 +1,64 @@
+package scrapper
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// errDuplicate is returned for a recipe with an ID which is already present.
+var errDuplicate = errors.New("Recipe with this ID is already present")
+
+// SkippedRow is a dump row which could not be parsed.
+type SkippedRow struct {
+	Row    int
+	ID     string
+	Reason string
+}
+
+// IngestReport describes what Scrap did with the dump rows.
+type IngestReport struct {
+	Rows         int
+	Parsed       int
+	Skipped      []*SkippedRow
+	Duplicates   []string
+	UnknownRaces []string
+}
+
+// Add appends counters and lists of another report, e.g. of the next dump file.
+func (r *IngestReport) Add(o *IngestReport) {
+	r.Rows += o.Rows
+	r.Parsed += o.Parsed
+	r.Skipped = append(r.Skipped, o.Skipped...)
+	r.Duplicates = append(r.Duplicates, o.Duplicates...)
+	r.UnknownRaces = append(r.UnknownRaces, o.UnknownRaces...)
+}
+
+func (r *IngestReport) skip(row int, id string, reason string) {
+	r.Skipped = append(r.Skipped, &SkippedRow{Row: row, ID: id, Reason: reason})
+}
+
+func (r *IngestReport) String() string {
+	rv := fmt.Sprintf("Rows: %v, parsed: %v, skipped: %v, duplicates: %v, unknown race: %v\n",
+		r.Rows, r.Parsed, len(r.Skipped), len(r.Duplicates), len(r.UnknownRaces))
+
+	if len(r.Skipped) > 0 {
+		rv += "Skipped:\n"
+		for _, s := range r.Skipped {
+			rv += fmt.Sprintf("\trow %v (%v): %v\n", s.Row, s.ID, s.Reason)
+		}
+	}
+	for _, sec := range []struct {
+		title string
+		list  []string
+	}{
+		{"Duplicates", r.Duplicates},
+		{"Unknown race", r.UnknownRaces},
+	} {
+		if len(sec.list) > 0 {
+			rv += fmt.Sprintf("%v:\n\t%v\n", sec.title, strings.Join(sec.list, "\n\t"))
+		}
+	}
+
+	return rv
+}
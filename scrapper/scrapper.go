@@ -3,9 +3,12 @@ package scrapper
 import (
 	"encoding/json"
 	"fmt"
+	"html"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/google/martian/v3/log"
 	"github.com/mebaranov/aioncraft/database"
@@ -17,37 +20,85 @@ type recipes struct {
 }
 
 type Scrapper struct {
+	Workers          int
+	Checkpoint       func()
+	CheckpointEvery  int
+	src              Source
 	nameRegex        *regexp.Regexp
 	itemIDCountRegex *regexp.Regexp
 	itemNameRegex    *regexp.Regexp
 }
 
 func New() *Scrapper {
+	return NewWithSource(NewHTTPSource(DefaultBaseURL))
+}
+
+func NewWithSource(src Source) *Scrapper {
 	return &Scrapper{
+		Workers:          DefaultWorkers,
+		CheckpointEvery:  DefaultCheckpointEvery,
+		src:              src,
 		nameRegex:        regexp.MustCompile(`<b>(.*?)</b>`),
 		itemIDCountRegex: regexp.MustCompile(`(?s)/usc/item/(.*?)/.*?<div class=\\?"quantity.*?>(\d+)</div>`),
 		itemNameRegex:    regexp.MustCompile(`\<span class="item_title.*?" id="item_name"\>\s*<b>(.*?)</b>`),
 	}
 }
 
-func (s *Scrapper) Scrap(in []byte, eElyon map[string]*database.Item, rElyon map[string]*database.Recipe, eAsmodian map[string]*database.Item, rAsmodian map[string]*database.Recipe) {
+// dumpColumns is the least amount of columns a dump row has: id, icon, name, level, ingredients and product.
+const dumpColumns = 6
+
+// Scrap parses the codex recipes dump into the race maps. Rows which can not be parsed, duplicates and rows of
+// recipes not bound to a race are skipped and listed in the report.
+func (s *Scrapper) Scrap(in []byte, eElyon map[string]*database.Item, rElyon map[string]*database.Recipe, eAsmodian map[string]*database.Item, rAsmodian map[string]*database.Recipe) (*IngestReport, error) {
+	rv := &IngestReport{}
 	data := &recipes{}
-	json.Unmarshal(in, data)
+	if err := json.Unmarshal(in, data); err != nil {
+		return rv, fmt.Errorf("Could not parse the dump. Error: %v", err)
+	}
+
+	rv.Rows = len(data.AaData)
+	for i, item := range data.AaData {
+		if len(item) < dumpColumns {
+			id := ""
+			if len(item) > 0 {
+				id = item[0]
+			}
+			rv.skip(i, id, fmt.Sprintf("Expected at least %v columns, got %v", dumpColumns, len(item)))
+			continue
+		}
+
+		var err error
+		switch {
+		case strings.Contains(item[2], "race-light"):
+			err = s.addRecipe(item, eElyon, rElyon)
+		case strings.Contains(item[2], "race-dark"):
+			err = s.addRecipe(item, eAsmodian, rAsmodian)
+		default:
+			rv.UnknownRaces = append(rv.UnknownRaces, item[0])
+			continue
+		}
 
-	for _, item := range data.AaData {
-		if strings.Contains(item[2], "race-light") {
-			s.addRecipe(item, eElyon, rElyon)
-		} else {
-			s.addRecipe(item, eAsmodian, rAsmodian)
+		switch {
+		case err == errDuplicate:
+			rv.Duplicates = append(rv.Duplicates, item[0])
+		case err != nil:
+			rv.skip(i, item[0], err.Error())
+		default:
+			rv.Parsed += 1
 		}
 	}
+
+	return rv, nil
 }
 
-func (s *Scrapper) addRecipe(item []string, e map[string]*database.Item, r map[string]*database.Recipe) {
+// addRecipe parses the dump row into the recipe. Items are added only if the whole row is valid.
+func (s *Scrapper) addRecipe(item []string, e map[string]*database.Item, r map[string]*database.Recipe) error {
 	id := item[0]
+	if id == "" {
+		return fmt.Errorf("Recipe has no ID")
+	}
 	if _, ok := r[id]; ok {
-		log.Errorf("Error: Recipe with this ID is already present: %v\n", id)
-		return
+		return errDuplicate
 	}
 
 	var err error
@@ -56,40 +107,31 @@ func (s *Scrapper) addRecipe(item []string, e map[string]*database.Item, r map[s
 
 	add.Level, err = strconv.Atoi(item[3])
 	if err != nil {
-		log.Errorf("Could not convert required level (%v): %v\n", item[3], item)
-		return
+		return fmt.Errorf("Could not convert required level (%v)", item[3])
 	}
 
-	add.Name = s.nameRegex.FindStringSubmatch(item[2])[1]
-	if add.Name == "" {
-		log.Errorf("Could not figure the name: %v\n", item)
-		return
+	name := s.nameRegex.FindStringSubmatch(item[2])
+	if name == nil || name[1] == "" {
+		return fmt.Errorf("Could not figure the name")
 	}
+	add.Name = strings.Replace(name[1], "&#39;", "'", -1)
 
 	idAndCount := s.itemIDCountRegex.FindStringSubmatch(item[5])
 	add.ItemID, add.Count, err = s.getIDAndCount(idAndCount)
 	if err != nil {
-		log.Errorf("Error at base recipe. %v: %v\n", err, item[5])
-		return
+		return fmt.Errorf("Error at base recipe. %v", err)
 	}
 
 	elements := s.itemIDCountRegex.FindAllStringSubmatch(item[4], -1)
 	if elements == nil {
-		log.Errorf("Could not figure recipe parts: %v\n", item)
-		return
+		return fmt.Errorf("Could not figure recipe parts")
 	}
 
 	add.Items = make(map[string]int)
 	for _, elem := range elements {
 		id, count, err := s.getIDAndCount(elem)
 		if err != nil {
-			log.Errorf("Error at elements. %v: %v\n", err, item[4])
-		}
-
-		if _, ok := e[id]; !ok {
-			e[id] = &database.Item{
-				ID: id,
-			}
+			return fmt.Errorf("Error at elements. %v", err)
 		}
 
 		if _, ok := add.Items[id]; ok {
@@ -99,6 +141,13 @@ func (s *Scrapper) addRecipe(item []string, e map[string]*database.Item, r map[s
 		add.Items[id] = count
 	}
 
+	for itemID := range add.Items {
+		if _, ok := e[itemID]; !ok {
+			e[itemID] = &database.Item{
+				ID: itemID,
+			}
+		}
+	}
 	if _, ok := e[add.ItemID]; !ok {
 		e[add.ItemID] = &database.Item{
 			ID: add.ItemID,
@@ -106,40 +155,198 @@ func (s *Scrapper) addRecipe(item []string, e map[string]*database.Item, r map[s
 	}
 
 	r[id] = add
-	add.Name = strings.Replace(add.Name, "&#39;", "'", -1)
+	return nil
+}
+
+const itemPathFmt = "%s/item/%s/"
+
+// Regions maps locales to the codex regions having item names in the language.
+var Regions = map[string]string{
+	database.DefaultLocale: "usc",
+	"de":                   "de",
+	"fr":                   "fr",
+	"ru":                   "ru",
+	"ko":                   "kr",
+}
+
+const (
+	DefaultWorkers         = 4
+	DefaultCheckpointEvery = 200
+)
+
+type nameJob struct {
+	id      string
+	locales []string
 }
 
-const addressFmt = "https://aioncodex.com/usc/item/%s/"
+type nameResult struct {
+	id    string
+	names map[string]string
+}
 
-func (s *Scrapper) Name(items map[string]*database.Item) {
-	req := NewRequester()
-	for id, item := range items {
-		if item.Name == "" {
-			data, err := req.GetData(fmt.Sprintf(addressFmt, id))
-			if err != nil {
-				log.Errorf("Could not load item data (%v). Error: %v", id, err)
+// Name fetches names of the items in the default locale and the additional ones.
+// Items already having a name in a locale are skipped, so naming resumes where it stopped when called again.
+// Maps may share item IDs (e.g. both races), every ID is fetched once.
+// Pages are loaded by Workers goroutines, while items are only changed by the calling one.
+// Checkpoint is called from the calling goroutine after every CheckpointEvery named items.
+func (s *Scrapper) Name(locales []string, maps ...map[string]*database.Item) {
+	jobs := []*nameJob{}
+	seen := map[string]bool{}
+	for _, items := range maps {
+		for id, item := range items {
+			if seen[id] {
 				continue
 			}
 
-			strData := string(data)
-			tmp := s.itemNameRegex.FindStringSubmatch(strData)
-			if len(tmp) != 2 {
-				log.Errorf("Wrong amount of sections in name (%v). %v\n", id, tmp)
-				continue
+			job := &nameJob{id: id}
+			if item.Name == "" {
+				job.locales = append(job.locales, database.DefaultLocale)
+			}
+			for _, locale := range locales {
+				if locale != database.DefaultLocale && item.Names[locale] == "" {
+					job.locales = append(job.locales, locale)
+				}
+			}
+			if len(job.locales) > 0 {
+				seen[id] = true
+				jobs = append(jobs, job)
 			}
+		}
+	}
+	defer initPrices(maps)
+	if len(jobs) == 0 {
+		return
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].id < jobs[j].id
+	})
+	log.Infof("Naming %v items", len(jobs))
 
-			item.Name = tmp[1]
-			item.Name = strings.Replace(item.Name, "&#39;", "'", -1)
+	results := make([]*nameResult, len(jobs))
+	s.pool(len(jobs), func(i int) {
+		job := jobs[i]
+		res := &nameResult{id: job.id, names: map[string]string{}}
+		for _, locale := range job.locales {
+			if name := s.fetchName(locale, job.id); name != "" {
+				res.names[locale] = name
+			}
+		}
+		results[i] = res
+	}, func(i int) {
+		for _, items := range maps {
+			if item, ok := items[results[i].id]; ok {
+				applyNames(item, results[i].names)
+			}
+		}
+	})
+}
+
+// pool runs fetch for jobs 0..count-1 with s.Workers concurrent workers. Apply is called for each finished job from
+// the calling goroutine, so it may change the database. Checkpoint is called every CheckpointEvery applied jobs.
+func (s *Scrapper) pool(count int, fetch func(i int), apply func(i int)) {
+	workers := s.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	jobc := make(chan int)
+	resc := make(chan int)
+	wg := &sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobc {
+				fetch(i)
+				resc <- i
+			}
+		}()
+	}
+	go func() {
+		for i := 0; i < count; i++ {
+			jobc <- i
+		}
+		close(jobc)
+		wg.Wait()
+		close(resc)
+	}()
+
+	done := 0
+	for i := range resc {
+		apply(i)
+
+		done += 1
+		if s.Checkpoint != nil && s.CheckpointEvery > 0 && done%s.CheckpointEvery == 0 {
+			log.Infof("Processed %v of %v", done, count)
+			s.Checkpoint()
+		}
+	}
+}
+
+// initPrices sets not available prices for items which have no price yet.
+func initPrices(maps []map[string]*database.Item) {
+	for _, items := range maps {
+		for _, item := range items {
+			if item.Price == nil {
+				item.Price = utility.NewInt(0, item.Name)
+			}
 		}
-		item.Price = utility.NewInt(0, item.Name)
 	}
 }
 
+func applyNames(item *database.Item, names map[string]string) {
+	for locale, name := range names {
+		if locale == database.DefaultLocale {
+			item.Name = name
+			continue
+		}
+
+		if item.Names == nil {
+			item.Names = map[string]string{}
+		}
+		item.Names[locale] = name
+	}
+}
+
+func (s *Scrapper) fetchName(locale string, id string) string {
+	region, ok := Regions[locale]
+	if !ok {
+		log.Errorf("Unknown locale (%v)", locale)
+		return ""
+	}
+
+	data, err := s.src.Get(fmt.Sprintf(itemPathFmt, region, id))
+	if err != nil {
+		log.Errorf("Could not load item data (%v, %v). Error: %v", id, locale, err)
+		return ""
+	}
+
+	name, err := s.itemName(data)
+	if err != nil {
+		log.Errorf("%v (%v, %v)\n", err, id, locale)
+		return ""
+	}
+
+	return name
+}
+
+func (s *Scrapper) itemName(page []byte) (string, error) {
+	tmp := s.itemNameRegex.FindSubmatch(page)
+	if len(tmp) != 2 {
+		return "", fmt.Errorf("Wrong amount of sections in name. %q", tmp)
+	}
+
+	return html.UnescapeString(string(tmp[1])), nil
+}
+
 func (s *Scrapper) getIDAndCount(item []string) (string, int, error) {
 	if len(item) != 3 {
 		return "", 0, fmt.Errorf("Unexpected elements count (%v)", item)
 	}
 
+	if item[1] == "" {
+		return "", 0, fmt.Errorf("Empty item ID (%v)", item)
+	}
+
 	count, err := strconv.Atoi(item[2])
 	if err != nil {
 		return "", 0, fmt.Errorf("Could not parse the count (%v)", item)
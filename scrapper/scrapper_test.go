@@ -0,0 +1,296 @@
+package scrapper
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/mebaranov/aioncraft/database"
+)
+
+func loadDump(t *testing.T) map[string][]string {
+	t.Helper()
+
+	data, err := ioutil.ReadFile("testdata/dump.json")
+	if err != nil {
+		t.Fatalf("Could not read dump: %v", err)
+	}
+
+	dump := &recipes{}
+	if err := json.Unmarshal(data, dump); err != nil {
+		t.Fatalf("Could not parse dump: %v", err)
+	}
+
+	rv := map[string][]string{}
+	for _, row := range dump.AaData {
+		rv[row[0]] = row
+	}
+	return rv
+}
+
+func TestGetIDAndCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      []string
+		id      string
+		count   int
+		wantErr bool
+	}{
+		{"valid", []string{"", "152000301", "10"}, "152000301", 10, false},
+		{"no match", nil, "", 0, true},
+		{"too few sections", []string{"", "152000301"}, "", 0, true},
+		{"bad count", []string{"", "152000301", "ten"}, "", 0, true},
+	}
+
+	s := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, count, err := s.getIDAndCount(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getIDAndCount(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if id != tt.id || count != tt.count {
+				t.Errorf("getIDAndCount(%q) = %v, %v; want %v, %v", tt.in, id, count, tt.id, tt.count)
+			}
+		})
+	}
+}
+
+func TestAddRecipe(t *testing.T) {
+	rows := loadDump(t)
+	tests := []struct {
+		name string
+		row  []string
+		want *database.Recipe
+	}{
+		{
+			name: "single ingredient",
+			row:  rows["155001195"],
+			want: &database.Recipe{
+				Name:   "Craft: Rose Quartz Powder",
+				ID:     "155001195",
+				ItemID: "152020099",
+				Level:  20,
+				Count:  10,
+				Items:  map[string]int{"152000301": 1},
+			},
+		},
+		{
+			name: "escaped name and several ingredients",
+			row:  rows["155001045"],
+			want: &database.Recipe{
+				Name:   "Craft: Virago's Feather Earrings",
+				ID:     "155001045",
+				ItemID: "120000804",
+				Level:  245,
+				Count:  1,
+				Items:  map[string]int{"152012012": 3, "152020072": 5, "152011051": 4, "152000905": 4},
+			},
+		},
+		{
+			name: "bad level",
+			row:  withColumn(rows["155001195"], 3, "twenty"),
+		},
+		{
+			name: "no ingredients",
+			row:  withColumn(rows["155001195"], 4, ""),
+		},
+		{
+			name: "no product",
+			row:  withColumn(rows["155001195"], 5, ""),
+		},
+	}
+
+	s := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := map[string]*database.Item{}
+			recs := map[string]*database.Recipe{}
+			s.addRecipe(tt.row, items, recs)
+
+			got := recs[tt.row[0]]
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("addRecipe() = %+v, want %+v", got, tt.want)
+			}
+			if tt.want == nil {
+				return
+			}
+
+			for id := range tt.want.Items {
+				if _, ok := items[id]; !ok {
+					t.Errorf("Ingredient %v is not added to items", id)
+				}
+			}
+			if _, ok := items[tt.want.ItemID]; !ok {
+				t.Errorf("Product %v is not added to items", tt.want.ItemID)
+			}
+		})
+	}
+}
+
+func TestAddRecipeDuplicate(t *testing.T) {
+	rows := loadDump(t)
+	s := New()
+	items := map[string]*database.Item{}
+	recs := map[string]*database.Recipe{}
+
+	s.addRecipe(rows["155001195"], items, recs)
+	first := recs["155001195"]
+	if err := s.addRecipe(withColumn(rows["155001195"], 3, "99"), items, recs); err != errDuplicate {
+		t.Errorf("addRecipe() error = %v, want %v", err, errDuplicate)
+	}
+
+	if recs["155001195"] != first || first.Level != 20 {
+		t.Errorf("Duplicate recipe replaced the first one: %+v", recs["155001195"])
+	}
+}
+
+func TestScrap(t *testing.T) {
+	rows := loadDump(t)
+	dump := &recipes{AaData: [][]string{
+		rows["155001195"],
+		rows["155006201"],
+		rows["155001195"],
+		rows["155090000"],
+		withColumn(rows["155001045"], 2, `<a class="race-light">no bold name</a>`),
+		withColumn(rows["155001045"], 5, ""),
+		{"155000001", "icon"},
+		{},
+	}}
+	data, err := json.Marshal(dump)
+	if err != nil {
+		t.Fatalf("Could not marshal dump: %v", err)
+	}
+
+	eItems, eRecs := map[string]*database.Item{}, map[string]*database.Recipe{}
+	aItems, aRecs := map[string]*database.Item{}, map[string]*database.Recipe{}
+	report, err := New().Scrap(data, eItems, eRecs, aItems, aRecs)
+	if err != nil {
+		t.Fatalf("Scrap() error = %v", err)
+	}
+
+	if report.Rows != 8 || report.Parsed != 2 {
+		t.Errorf("Rows = %v, parsed = %v; want 8, 2", report.Rows, report.Parsed)
+	}
+	if !reflect.DeepEqual(report.Duplicates, []string{"155001195"}) {
+		t.Errorf("Duplicates = %v", report.Duplicates)
+	}
+	if !reflect.DeepEqual(report.UnknownRaces, []string{"155090000"}) {
+		t.Errorf("UnknownRaces = %v", report.UnknownRaces)
+	}
+
+	skipped := []int{}
+	for _, s := range report.Skipped {
+		skipped = append(skipped, s.Row)
+		if s.Reason == "" {
+			t.Errorf("No reason for skipped row %v", s.Row)
+		}
+	}
+	if !reflect.DeepEqual(skipped, []int{4, 5, 6, 7}) {
+		t.Errorf("Skipped rows = %v, want [4 5 6 7]", skipped)
+	}
+	if _, ok := eItems["152012012"]; ok {
+		t.Errorf("Ingredient of a skipped recipe is added to items")
+	}
+	if len(eRecs)+len(aRecs) != 2 {
+		t.Errorf("Got %v recipes, want 2", len(eRecs)+len(aRecs))
+	}
+}
+
+func TestScrapInvalidJson(t *testing.T) {
+	if _, err := New().Scrap([]byte("{not json"), nil, nil, nil, nil); err == nil {
+		t.Errorf("Scrap() accepted invalid JSON")
+	}
+}
+
+func TestItemName(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{"english", "usc/item/152000301/", "Rose Quartz", false},
+		{"german", "de/item/152000301/", "Rosenquarz", false},
+		{"escaped", "usc/item/152020099/", "Tahabata's Powder", false},
+		{"missing name", "usc/item/999999999/", "", true},
+	}
+
+	src := NewDirSource("testdata/pages")
+	s := NewWithSource(src)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := src.Get(tt.path)
+			if err != nil {
+				t.Fatalf("Could not load fixture: %v", err)
+			}
+
+			got, err := s.itemName(page)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("itemName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("itemName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		name string
+		src  func(t *testing.T) Source
+	}{
+		{"dir", func(t *testing.T) Source {
+			return NewDirSource("testdata/pages")
+		}},
+		{"http", func(t *testing.T) Source {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				page, err := NewDirSource("testdata/pages").Get(r.URL.Path)
+				if err != nil {
+					http.NotFound(w, r)
+					return
+				}
+				w.Write(page)
+			}))
+			t.Cleanup(srv.Close)
+			return NewHTTPSource(srv.URL + "/")
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := map[string]*database.Item{
+				"152000301": {ID: "152000301"},
+				"152020099": {ID: "152020099", Name: "Already Named"},
+				"999999999": {ID: "999999999"},
+			}
+
+			NewWithSource(tt.src(t)).Name([]string{"de"}, items)
+
+			if got := items["152000301"]; got.Name != "Rose Quartz" || got.Names["de"] != "Rosenquarz" {
+				t.Errorf("Wrong names: %q, %v", got.Name, got.Names)
+			}
+			if got := items["152020099"].Name; got != "Already Named" {
+				t.Errorf("Named item was renamed to %q", got)
+			}
+			if got := items["999999999"].Name; got != "" {
+				t.Errorf("Item without name page got name %q", got)
+			}
+			for id, it := range items {
+				if it.Price == nil {
+					t.Errorf("Price is not initialized for %v", id)
+				}
+			}
+		})
+	}
+}
+
+func withColumn(row []string, idx int, value string) []string {
+	rv := append([]string(nil), row...)
+	rv[idx] = value
+	return rv
+}
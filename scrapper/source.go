@@ -0,0 +1,51 @@
+package scrapper
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+const DefaultBaseURL = "https://aioncodex.com"
+
+// Source provides codex pages by their path, e.g. "usc/item/152000301/".
+type Source interface {
+	Get(path string) ([]byte, error)
+}
+
+// HTTPSource loads pages from the codex site or any server mirroring its paths.
+type HTTPSource struct {
+	BaseURL   string
+	Requester *Requester
+}
+
+func NewHTTPSource(baseURL string) *HTTPSource {
+	return &HTTPSource{
+		BaseURL:   strings.TrimSuffix(baseURL, "/"),
+		Requester: NewRequester(),
+	}
+}
+
+func (h *HTTPSource) Get(path string) ([]byte, error) {
+	return h.Requester.GetData(h.BaseURL + "/" + strings.TrimPrefix(path, "/"))
+}
+
+// DirSource loads pages saved into a directory. Page "usc/item/152000301/" is read from "<Dir>/usc/item/152000301.html".
+type DirSource struct {
+	Dir string
+}
+
+func NewDirSource(dir string) *DirSource {
+	return &DirSource{Dir: dir}
+}
+
+func (d *DirSource) Get(path string) ([]byte, error) {
+	file := filepath.Join(d.Dir, filepath.FromSlash(strings.Trim(path, "/"))+".html")
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("Could not load saved page. Error: %v", err)
+	}
+
+	return data, nil
+}
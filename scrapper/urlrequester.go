@@ -4,34 +4,95 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 	"time"
 )
 
-type Requester struct {
-	lastTime time.Time
+const (
+	DefaultRate    = 5.0
+	DefaultRetries = 4
+	DefaultBackoff = time.Second
+	requestTimeout = 30 * time.Second
+)
+
+// StatusError is returned for unsuccessful HTTP responses.
+type StatusError struct {
+	Code       int
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("Unexpected status: %v %v", e.Code, http.StatusText(e.Code))
 }
 
-const Delay = time.Millisecond * 10
+// Temporary reports if the request may succeed when repeated.
+func (e *StatusError) Temporary() bool {
+	return e.Code == http.StatusTooManyRequests || e.Code >= 500
+}
+
+type Requester struct {
+	Retries int
+	Backoff time.Duration
+	limiter *Limiter
+	client  *http.Client
+}
 
 func NewRequester() *Requester {
+	return NewRequesterWithRate(DefaultRate)
+}
+
+func NewRequesterWithRate(rate float64) *Requester {
 	return &Requester{
-		lastTime: time.Now(),
+		Retries: DefaultRetries,
+		Backoff: DefaultBackoff,
+		limiter: NewLimiter(rate, int(rate)),
+		client:  &http.Client{Timeout: requestTimeout},
 	}
 }
 
+// GetData loads the page. Requests are rate limited and repeated with exponential backoff on 429, 5xx and network errors.
 func (r *Requester) GetData(url string) ([]byte, error) {
-	if r.lastTime.After(time.Now()) {
-		time.Sleep(r.lastTime.Add(time.Second).Sub(time.Now()))
+	for attempt := 0; ; attempt++ {
+		r.limiter.Wait()
+
+		data, err := r.get(url)
+		if err == nil {
+			return data, nil
+		}
+
+		delay := r.Backoff << uint(attempt)
+		if se, ok := err.(*StatusError); ok {
+			if !se.Temporary() {
+				return nil, err
+			}
+			if se.RetryAfter > delay {
+				delay = se.RetryAfter
+			}
+		}
+		if attempt >= r.Retries {
+			return nil, fmt.Errorf("Giving up after %v attempts. Error: %v", attempt+1, err)
+		}
+
+		time.Sleep(delay)
 	}
+}
 
-	resp, err := http.Get(url)
-	r.lastTime = r.lastTime.Add(Delay)
+func (r *Requester) get(url string) ([]byte, error) {
+	resp, err := r.client.Get(url)
 	if err != nil {
 		return nil, fmt.Errorf("Could not load data. Error: %v", err)
 	}
 
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		se := &StatusError{Code: resp.StatusCode}
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			se.RetryAfter = time.Duration(secs) * time.Second
+		}
+		return nil, se
+	}
+
 	data, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("Could not read response body. Error: %v", err)
@@ -0,0 +1,103 @@
+package scrapper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mebaranov/aioncraft/database"
+)
+
+func TestGetDataRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		wantErr  bool
+		wantHits int
+	}{
+		{"ok", []int{200}, false, 1},
+		{"too many requests", []int{429, 429, 200}, false, 3},
+		{"server error", []int{503, 200}, false, 2},
+		{"not found", []int{404, 200}, true, 1},
+		{"gives up", []int{500, 500, 500}, true, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statuses[hits])
+				hits += 1
+				w.Write([]byte("page"))
+			}))
+			defer srv.Close()
+
+			r := NewRequesterWithRate(0)
+			r.Retries = 2
+			r.Backoff = time.Millisecond
+
+			data, err := r.GetData(srv.URL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetData() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(data) != "page" {
+				t.Errorf("GetData() = %q", data)
+			}
+			if hits != tt.wantHits {
+				t.Errorf("Server was hit %v times, want %v", hits, tt.wantHits)
+			}
+		})
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	l := NewLimiter(50, 2)
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		l.Wait()
+	}
+
+	// 2 requests are a burst, 4 more take 4/50 of a second
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+		t.Errorf("6 requests took %v, limiter does not wait", elapsed)
+	}
+}
+
+type countingSource struct {
+	Source
+	hits map[string]int
+}
+
+func (c *countingSource) Get(path string) ([]byte, error) {
+	c.hits[path] += 1
+	return c.Source.Get(path)
+}
+
+func TestNameCheckpointAndResume(t *testing.T) {
+	src := &countingSource{NewDirSource("testdata/pages"), map[string]int{}}
+	s := NewWithSource(src)
+	s.Workers = 1
+	s.CheckpointEvery = 1
+
+	elyos := map[string]*database.Item{"152000301": {ID: "152000301"}, "152020099": {ID: "152020099"}}
+	asmo := map[string]*database.Item{"152000301": {ID: "152000301"}}
+	checkpoints := 0
+	s.Checkpoint = func() { checkpoints += 1 }
+
+	s.Name(nil, elyos, asmo)
+	if checkpoints != 2 {
+		t.Errorf("Checkpoint was called %v times, want 2", checkpoints)
+	}
+	if src.hits["usc/item/152000301/"] != 1 {
+		t.Errorf("Shared item was loaded %v times", src.hits["usc/item/152000301/"])
+	}
+	if asmo["152000301"].Name != "Rose Quartz" {
+		t.Errorf("Shared item is not named for the second race: %q", asmo["152000301"].Name)
+	}
+
+	s.Name(nil, elyos, asmo)
+	if src.hits["usc/item/152000301/"] != 1 || src.hits["usc/item/152020099/"] != 1 {
+		t.Errorf("Named items were loaded again: %v", src.hits)
+	}
+}
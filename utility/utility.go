@@ -1,6 +1,7 @@
 package utility
 
 import (
+	"bytes"
 	"strings"
 	"time"
 
@@ -45,3 +46,25 @@ func sendMonitored(s *discordgo.Session, c *string, msg *string) {
 func SendMonitored(s *discordgo.Session, c *string, msg *string) {
 	go sendMonitored(s, c, msg)
 }
+
+func sendFile(s *discordgo.Session, c *string, name string, data []byte) {
+	s.ChannelFileSend(*c, name, bytes.NewReader(data))
+}
+
+func SendFile(s *discordgo.Session, c *string, name string, data []byte) {
+	go sendFile(s, c, name, data)
+}
+
+func sendFileWithMessage(s *discordgo.Session, c *string, msg *string, name string, data []byte) {
+	if len(*msg) < charLimit {
+		s.ChannelFileSendWithMessage(*c, *msg, name, bytes.NewReader(data))
+		return
+	}
+	sendMonitored(s, c, msg)
+	sendFile(s, c, name, data)
+}
+
+// SendFileWithMessage attaches the file to the message. Long messages are split and the file is sent after them.
+func SendFileWithMessage(s *discordgo.Session, c *string, msg *string, name string, data []byte) {
+	go sendFileWithMessage(s, c, msg, name, data)
+}